@@ -0,0 +1,167 @@
+package wpml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ActionGroupIDAllocator assigns wpml:actionGroupId values during conversion.
+// It is called once per action group, in waypoint order, and the returned ID
+// is used for that group in both template.kml and waylines.wpml. IDs must be
+// unique within the wayline and in the 0 to 65535 range, or the conversion
+// fails.
+type ActionGroupIDAllocator interface {
+	AllocateActionGroupID(site ActionGroupSite) int
+}
+
+// ActionGroupSite describes the action group an ID is allocated for: the
+// index and position of the waypoint it starts at and its trigger type.
+type ActionGroupSite struct {
+	WaypointIndex int
+	Position      LatLng
+	TriggerType   string
+}
+
+// MonotonicActionGroupIDAllocator hands out 0, 1, 2, ... in allocation order.
+// It is the default allocator.
+type MonotonicActionGroupIDAllocator struct {
+	next int
+}
+
+func NewMonotonicActionGroupIDAllocator() *MonotonicActionGroupIDAllocator {
+	return &MonotonicActionGroupIDAllocator{}
+}
+
+func (a *MonotonicActionGroupIDAllocator) AllocateActionGroupID(site ActionGroupSite) int {
+	id := a.next
+	a.next++
+	return id
+}
+
+// PreservingActionGroupIDAllocator reuses the action group IDs of a parsed
+// mission, matching each group by the position of the waypoint it starts at
+// and its trigger type rather than by waypoint index, so inserting or removing
+// waypoints does not renumber the groups after them. Groups that match none in
+// the parsed mission get fresh IDs above the largest existing one, so
+// re-rendering an edited mission only changes the IDs of new or moved groups.
+type PreservingActionGroupIDAllocator struct {
+	existing map[actionGroupKey][]int
+	used     map[int]bool
+	next     int
+}
+
+// actionGroupKey is what PreservingActionGroupIDAllocator matches groups on.
+// Coordinates are formatted as the converter writes them, so a parsed group
+// and its re-rendered counterpart compare equal.
+type actionGroupKey struct {
+	coordinates string
+	triggerType string
+}
+
+func NewPreservingActionGroupIDAllocator(mission *WPMLMission) *PreservingActionGroupIDAllocator {
+	a := &PreservingActionGroupIDAllocator{
+		existing: make(map[actionGroupKey][]int),
+		used:     make(map[int]bool),
+	}
+	if mission == nil || mission.Waylines == nil {
+		return a
+	}
+
+	for _, folder := range mission.Waylines.Document.Folders {
+		for _, placemark := range folder.Placemarks {
+			position, ok := placemarkPosition(placemark)
+			for _, group := range placemark.ActionGroups {
+				if ok {
+					key := actionGroupKey{
+						coordinates: formatCoordinates(position.Longitude, position.Latitude),
+						triggerType: group.ActionTrigger.ActionTriggerType,
+					}
+					a.existing[key] = append(a.existing[key], group.ActionGroupID)
+				}
+				if group.ActionGroupID >= a.next {
+					a.next = group.ActionGroupID + 1
+				}
+			}
+		}
+	}
+
+	return a
+}
+
+func (a *PreservingActionGroupIDAllocator) AllocateActionGroupID(site ActionGroupSite) int {
+	key := actionGroupKey{
+		coordinates: formatCoordinates(site.Position.Longitude, site.Position.Latitude),
+		triggerType: site.TriggerType,
+	}
+	for len(a.existing[key]) > 0 {
+		id := a.existing[key][0]
+		a.existing[key] = a.existing[key][1:]
+		if !a.used[id] {
+			a.used[id] = true
+			return id
+		}
+	}
+
+	for a.used[a.next] {
+		a.next++
+	}
+	id := a.next
+	a.used[id] = true
+	a.next++
+	return id
+}
+
+// placemarkPosition parses the "longitude,latitude" coordinates of a
+// placemark point.
+func placemarkPosition(placemark Placemark) (LatLng, bool) {
+	if placemark.Point == nil {
+		return LatLng{}, false
+	}
+	lon, lat, ok := strings.Cut(strings.TrimSpace(placemark.Point.Coordinates), ",")
+	if !ok {
+		return LatLng{}, false
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return LatLng{}, false
+	}
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return LatLng{}, false
+	}
+	return LatLng{Latitude: latitude, Longitude: longitude}, true
+}
+
+// ActionGroupCount returns the number of action groups the converter emits:
+// one for each waypoint with actions and one for each IntervalCapture.
+func (w *Waylines) ActionGroupCount() int {
+	count := 0
+	for i := range w.Waypoints {
+		count += len(w.actionGroups(i))
+	}
+	return count
+}
+
+// maxActionGroupID is the largest value wpml:actionGroupId accepts.
+const maxActionGroupID = 65535
+
+// validateActionGroupCount rejects missions with more action groups than
+// wpml:actionGroupId can number. DJI does not publish a lower per-model
+// firmware limit; ValidateActionGroupCount checks a limit of your own.
+func validateActionGroupCount(w *Waylines) error {
+	if count := w.ActionGroupCount(); count > maxActionGroupID+1 {
+		return fmt.Errorf(ErrActionGroupIDsExhausted, count, maxActionGroupID+1)
+	}
+	return nil
+}
+
+// ValidateActionGroupCount returns an error when the mission has more than
+// limit action groups, such as the limit a controller and firmware version
+// reported on a failed upload.
+func (w *Waylines) ValidateActionGroupCount(limit int) error {
+	if count := w.ActionGroupCount(); count > limit {
+		return fmt.Errorf(ErrTooManyActionGroups, count, limit)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package wpml
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func photoAction() ActionRequest {
+	return ActionRequest{
+		Type:   ActionTypeTakePhoto,
+		Action: &TakePhotoAction{PayloadPositionIndex: PayloadPosition0},
+	}
+}
+
+func waylinesWithActionsAt(name string, count int, withActions ...int) *Waylines {
+	waylines := createValidWaylines(name)
+	base := waylines.Waypoints[0]
+	waylines.Waypoints = make([]WaylinesWaypoint, count)
+	for i := range waylines.Waypoints {
+		waylines.Waypoints[i] = base
+		waylines.Waypoints[i].Latitude += float64(i) * 0.001
+	}
+	for _, i := range withActions {
+		waylines.Waypoints[i].Actions = []ActionRequest{photoAction()}
+	}
+	return waylines
+}
+
+func waylineActionGroupIDs(mission *WPMLMission) map[int]int {
+	ids := make(map[int]int)
+	for _, placemark := range mission.Waylines.Document.Folders[0].Placemarks {
+		for _, group := range placemark.ActionGroups {
+			ids[placemark.Index] = group.ActionGroupID
+		}
+	}
+	return ids
+}
+
+func TestMonotonicActionGroupIDAllocator(t *testing.T) {
+	allocator := NewMonotonicActionGroupIDAllocator()
+
+	assert.Equal(t, 0, allocator.AllocateActionGroupID(ActionGroupSite{WaypointIndex: 3}))
+	assert.Equal(t, 1, allocator.AllocateActionGroupID(ActionGroupSite{WaypointIndex: 7}))
+	assert.Equal(t, 2, allocator.AllocateActionGroupID(ActionGroupSite{WaypointIndex: 9}))
+}
+
+func TestConvert_DefaultActionGroupIDs(t *testing.T) {
+	waylines := waylinesWithActionsAt("IDs", 4, 1, 3)
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]int{1: 0, 3: 1}, waylineActionGroupIDs(mission))
+
+	templateGroups := mission.Template.Document.Folders[0].Placemarks[3].ActionGroups
+	require.Len(t, templateGroups, 1)
+	assert.Equal(t, 1, templateGroups[0].ActionGroupID)
+}
+
+func TestConvert_InvalidAllocatedActionGroupIDs(t *testing.T) {
+	sequence := func(ids ...int) func() int {
+		return func() int {
+			id := ids[0]
+			ids = ids[1:]
+			return id
+		}
+	}
+
+	tests := []struct {
+		name     string
+		idSource func() int
+		expected string
+	}{
+		{name: "negative", idSource: func() int { return -1 }, expected: "waypoint 0 was allocated action group ID -1, outside the 0 to 65535 range"},
+		{name: "above the maximum", idSource: sequence(0, 65536, 2), expected: "waypoint 1 was allocated action group ID 65536"},
+		{name: "duplicate", idSource: sequence(4, 5, 4), expected: "waypoint 2 was allocated action group ID 4, which waypoint 0 already uses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("IDs", 3, 0, 1, 2)
+
+			_, err := ConvertWaylinesToWPMLMissionWithOptions(waylines, RenderOptions{IDSource: tt.idSource})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+
+	waylines := waylinesWithActionsAt("IDs", 3, 0, 1, 2)
+	mission, err := ConvertWaylinesToWPMLMissionWithOptions(waylines, RenderOptions{IDSource: sequence(65535, 0, 7)})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 65535, 1: 0, 2: 7}, waylineActionGroupIDs(mission))
+}
+
+func TestPreservingActionGroupIDAllocator(t *testing.T) {
+	original, err := ConvertWaylinesToWPMLMission(waylinesWithActionsAt("IDs", 4, 1, 3))
+	require.NoError(t, err)
+
+	kmz, err := CreateKmzBuffer(original)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+
+	edited := waylinesWithActionsAt("IDs", 4, 0, 1, 3)
+	mission, err := ConvertWaylinesToWPMLMissionWithOptions(edited, RenderOptions{
+		ActionGroupIDAllocator: NewPreservingActionGroupIDAllocator(parsed),
+	})
+	require.NoError(t, err)
+
+	ids := waylineActionGroupIDs(mission)
+	assert.Equal(t, 0, ids[1])
+	assert.Equal(t, 1, ids[3])
+	assert.Equal(t, 2, ids[0], "new group gets an ID above existing ones")
+}
+
+func TestPreservingActionGroupIDAllocator_InsertAndRemoveWaypoints(t *testing.T) {
+	original := waylinesWithActionsAt("IDs", 4, 1, 2, 3)
+	rendered, err := ConvertWaylinesToWPMLMission(original)
+	require.NoError(t, err)
+	kmz, err := CreateKmzBuffer(rendered)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+
+	// Insert a waypoint with actions before waypoint 1 and drop waypoint 2.
+	inserted := original.Waypoints[0]
+	inserted.Longitude += 0.001
+	inserted.Actions = []ActionRequest{photoAction()}
+	edited := createValidWaylines("IDs")
+	edited.Waypoints = []WaylinesWaypoint{original.Waypoints[0], inserted, original.Waypoints[1], original.Waypoints[3]}
+
+	mission, err := ConvertWaylinesToWPMLMissionWithOptions(edited, RenderOptions{
+		ActionGroupIDAllocator: NewPreservingActionGroupIDAllocator(parsed),
+	})
+	require.NoError(t, err)
+
+	ids := waylineActionGroupIDs(mission)
+	assert.Equal(t, 0, ids[2], "the group of original waypoint 1 keeps its ID")
+	assert.Equal(t, 2, ids[3], "the group of original waypoint 3 keeps its ID")
+	assert.Equal(t, 3, ids[1], "the inserted group gets a fresh ID")
+}
+
+func TestPreservingActionGroupIDAllocator_NilMission(t *testing.T) {
+	allocator := NewPreservingActionGroupIDAllocator(nil)
+
+	assert.Equal(t, 0, allocator.AllocateActionGroupID(ActionGroupSite{WaypointIndex: 5}))
+	assert.Equal(t, 1, allocator.AllocateActionGroupID(ActionGroupSite{WaypointIndex: 6}))
+}
+
+func TestValidateActionGroupCount(t *testing.T) {
+	waylines := waylinesWithActionsAt("Groups", 4, 0, 2)
+	assert.Equal(t, 2, waylines.ActionGroupCount())
+	assert.NoError(t, validateActionGroupCount(waylines))
+	assert.NoError(t, waylines.ValidateActionGroupCount(2))
+
+	err := waylines.ValidateActionGroupCount(1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mission has 2 action groups, more than the limit of 1")
+
+	waylines.Waypoints = make([]WaylinesWaypoint, maxActionGroupID+2)
+	for i := range waylines.Waypoints {
+		waylines.Waypoints[i].Actions = []ActionRequest{photoAction()}
+	}
+	err = validateActionGroupCount(waylines)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("mission has %d action groups", maxActionGroupID+2))
+}
@@ -0,0 +1,118 @@
+package wpml
+
+import "fmt"
+
+// actionTriggerTypes are the triggers DJI action groups accept.
+var actionTriggerTypes = []string{
+	TriggerTypeReachPoint,
+	TriggerTypeBetweenAdjacentPoints,
+	TriggerTypeMultipleTiming,
+	TriggerTypeMultipleDistance,
+}
+
+// actionTrigger returns the trigger of the waypoint's action group. A waypoint
+// ActionTrigger is used as is, which keeps the group trigger of missions
+// parsed from DJI Pilot independent of the waypoint TriggerType. Otherwise the
+// trigger is built from TriggerType, defaulting to reachPoint, with
+// TriggerParam as the interval of multipleTiming and multipleDistance.
+func (wp WaylinesWaypoint) actionTrigger() ActionTrigger {
+	if wp.ActionTrigger != nil {
+		return *wp.ActionTrigger
+	}
+
+	trigger := ActionTrigger{ActionTriggerType: TriggerTypeReachPoint}
+	switch wp.TriggerType {
+	case TriggerTypePassPoint, TriggerTypeManual, TriggerTypeBetweenAdjacentPoints:
+		trigger.ActionTriggerType = wp.TriggerType
+	case TriggerTypeMultipleTiming, TriggerTypeMultipleDistance:
+		trigger.ActionTriggerType = wp.TriggerType
+		if wp.TriggerParam > 0 {
+			trigger.ActionTriggerParam = float64Ptr(wp.TriggerParam)
+		}
+	}
+	return trigger
+}
+
+func (t ActionTrigger) param() float64 {
+	if t.ActionTriggerParam == nil {
+		return 0
+	}
+	return *t.ActionTriggerParam
+}
+
+// isLegTrigger reports whether a trigger runs its actions on the leg after the
+// waypoint rather than at the waypoint itself.
+func isLegTrigger(triggerType string) bool {
+	return isIntervalTrigger(triggerType) || triggerType == TriggerTypeBetweenAdjacentPoints
+}
+
+// waypointActionGroup is an action group of a waypoint before conversion.
+type waypointActionGroup struct {
+	trigger ActionTrigger
+	actions []ActionRequest
+}
+
+// actionGroups returns the action groups the converter emits for waypoint
+// index, in order: the group of its Actions, with the hovers stop-and-go
+// missions add, and the group of its IntervalCapture.
+func (w *Waylines) actionGroups(index int) []waypointActionGroup {
+	wp := w.Waypoints[index]
+	var groups []waypointActionGroup
+	if len(wp.Actions) > 0 {
+		groups = append(groups, waypointActionGroup{trigger: wp.actionTrigger(), actions: w.effectiveActions(wp)})
+	}
+	if capture := wp.IntervalCapture; capture != nil {
+		groups = append(groups, waypointActionGroup{
+			trigger: capture.trigger(),
+			actions: []ActionRequest{{
+				Type:   ActionTypeTakePhoto,
+				Action: &TakePhotoAction{PayloadPositionIndex: w.PayloadPositionIndex},
+			}},
+		})
+	}
+	return groups
+}
+
+// actionGroupEndIndex returns the wpml:actionGroupEndIndex of a group with
+// trigger starting at waypoint index. A leg trigger group ends at the next
+// waypoint, which closes an interval capture when the aircraft reaches it;
+// every other group starts and ends at its own waypoint.
+func (w *Waylines) actionGroupEndIndex(index int, trigger ActionTrigger) int {
+	if isLegTrigger(trigger.ActionTriggerType) && index < len(w.Waypoints)-1 {
+		return index + 1
+	}
+	return index
+}
+
+func isIntervalTrigger(triggerType string) bool {
+	return triggerType == TriggerTypeMultipleTiming || triggerType == TriggerTypeMultipleDistance
+}
+
+// validateActionTriggers checks waypoint ActionTriggers against their type:
+// multipleTiming needs an interval in seconds and multipleDistance one in
+// meters, while reachPoint and betweenAdjacentPoints take no parameter.
+func validateActionTriggers(w *Waylines) error {
+	for i, wp := range w.Waypoints {
+		trigger := wp.ActionTrigger
+		if trigger == nil {
+			continue
+		}
+		switch trigger.ActionTriggerType {
+		case TriggerTypeMultipleTiming, TriggerTypeMultipleDistance:
+			if trigger.param() <= 0 {
+				unit := "seconds"
+				if trigger.ActionTriggerType == TriggerTypeMultipleDistance {
+					unit = "meters"
+				}
+				return fmt.Errorf(ErrActionTriggerParamRequired, i, trigger.ActionTriggerType, unit)
+			}
+		case TriggerTypeReachPoint, TriggerTypeBetweenAdjacentPoints:
+			if trigger.ActionTriggerParam != nil {
+				return fmt.Errorf(ErrActionTriggerParamUnexpected, i, trigger.ActionTriggerType, *trigger.ActionTriggerParam)
+			}
+		default:
+			return fmt.Errorf(ErrUnknownActionTriggerType, i, trigger.ActionTriggerType, actionTriggerTypes)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert_ActionGroupTrigger(t *testing.T) {
+	waylines := waylinesWithActionsAt("Group Trigger", 3, 0, 1)
+	waylines.Waypoints[0].TriggerType = TriggerTypeReachPoint
+	waylines.Waypoints[0].ActionTrigger = &ActionTrigger{
+		ActionTriggerType:  TriggerTypeMultipleTiming,
+		ActionTriggerParam: float64Ptr(3),
+	}
+	waylines.Waypoints[1].TriggerType = TriggerTypeMultipleDistance
+	waylines.Waypoints[1].TriggerParam = 20
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	placemarks := mission.Waylines.Document.Folders[0].Placemarks
+	first := placemarks[0].ActionGroups[0].ActionTrigger
+	assert.Equal(t, TriggerTypeMultipleTiming, first.ActionTriggerType)
+	assert.Equal(t, 3.0, *first.ActionTriggerParam)
+
+	second := placemarks[1].ActionGroups[0].ActionTrigger
+	assert.Equal(t, TriggerTypeMultipleDistance, second.ActionTriggerType)
+	assert.Equal(t, 20.0, *second.ActionTriggerParam, "the waypoint interval carries over")
+
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, first, parsed.Waylines.Document.Folders[0].Placemarks[0].ActionGroups[0].ActionTrigger)
+}
+
+func TestValidateActionTriggers(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger ActionTrigger
+		wantErr string
+	}{
+		{name: "Reach point", trigger: ActionTrigger{ActionTriggerType: TriggerTypeReachPoint}},
+		{name: "Timed interval", trigger: ActionTrigger{ActionTriggerType: TriggerTypeMultipleTiming, ActionTriggerParam: float64Ptr(2)}},
+		{
+			name:    "Timed interval without a parameter",
+			trigger: ActionTrigger{ActionTriggerType: TriggerTypeMultipleTiming},
+			wantErr: "waypoint 0 action trigger multipleTiming needs a positive interval in seconds",
+		},
+		{
+			name:    "Distance interval of zero",
+			trigger: ActionTrigger{ActionTriggerType: TriggerTypeMultipleDistance, ActionTriggerParam: float64Ptr(0)},
+			wantErr: "needs a positive interval in meters",
+		},
+		{
+			name:    "Reach point with a parameter",
+			trigger: ActionTrigger{ActionTriggerType: TriggerTypeReachPoint, ActionTriggerParam: float64Ptr(5)},
+			wantErr: "action trigger reachPoint takes no parameter, got 5",
+		},
+		{
+			name:    "Unknown type",
+			trigger: ActionTrigger{ActionTriggerType: TriggerTypeManual},
+			wantErr: `action trigger type "manual" is not one of`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Group Trigger", 2, 0)
+			waylines.Waypoints[0].ActionTrigger = &tt.trigger
+
+			err := waylines.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPhotoCount_ActionGroupTrigger(t *testing.T) {
+	waylines := waylinesAt("Group Trigger", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	waylines.Waypoints[0].Actions = []ActionRequest{photoAction()}
+	waylines.Waypoints[0].ActionTrigger = &ActionTrigger{
+		ActionTriggerType:  TriggerTypeMultipleDistance,
+		ActionTriggerParam: float64Ptr(20),
+	}
+
+	// 111m leg at 20m spacing: the shot at the waypoint and five along the leg.
+	assert.Equal(t, 6, waylines.PhotoCount())
+}
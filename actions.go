@@ -79,7 +79,7 @@ func (a *CustomDirNameAction) GetActionType() string {
 type GimbalRotateAction struct {
 	PayloadPositionIndex    PayloadPosition `json:"payload_position_index" validate:"payload_position"`
 	GimbalHeadingYawBase    string          `json:"gimbal_heading_yaw_base" validate:"required"`
-	GimbalRotateMode        string          `json:"gimbal_rotate_mode" validate:"required"`
+	GimbalRotateMode        string          `json:"gimbal_rotate_mode" validate:"omitempty,oneof=absoluteAngle relativeAngle"`
 	GimbalPitchRotateEnable bool            `json:"gimbal_pitch_rotate_enable"`
 	GimbalPitchRotateAngle  float64         `json:"gimbal_pitch_rotate_angle"`
 	GimbalRollRotateEnable  bool            `json:"gimbal_roll_rotate_enable"`
@@ -95,7 +95,7 @@ func (a *GimbalRotateAction) GetActionType() string {
 }
 
 type RotateYawAction struct {
-	AircraftHeading  float64 `json:"aircraft_heading" validate:"required,min=-180,max=180"`
+	AircraftHeading  float64 `json:"aircraft_heading" validate:"min=-180,max=180"`
 	AircraftPathMode *string `json:"aircraft_path_mode,omitempty"`
 }
 
@@ -0,0 +1,181 @@
+package wpml
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// MergePolicy decides which global setting Append keeps when the two missions
+// disagree.
+type MergePolicy string
+
+const (
+	// MergePolicyErrorOnConflict fails on the first setting the missions
+	// disagree on.
+	MergePolicyErrorOnConflict MergePolicy = "errorOnConflict"
+	// MergePolicyPreferFirst keeps the setting of the first mission.
+	MergePolicyPreferFirst MergePolicy = "preferFirst"
+	// MergePolicyPreferSafer keeps the safer setting where one of them is
+	// safer, and the first mission's setting otherwise.
+	MergePolicyPreferSafer MergePolicy = "preferSafer"
+)
+
+// Append returns a new mission that flies w's waypoints followed by next's.
+// Neither mission is modified, although the actions of the copied waypoints
+// are shared with them. Global settings are merged field by field:
+//
+//   - DroneModel, PayloadModel, PayloadPositionIndex, AdditionalPayloads,
+//     TemplateType and HeightType must match under every policy, since the
+//     waypoints of one mission would mean something else under the other's.
+//   - Name, Description and the takeoff reference point always come from w.
+//   - PhotoSettings is the union of both missions' lenses.
+//   - Metadata is the union of both maps; a key with two different values is
+//     a conflict.
+//   - Under MergePolicyPreferSafer, GlobalHeight, SafeHeight and
+//     GlobalRTHHeight take the higher value and GlobalSpeed and
+//     GlobalTransitionalSpeed the lower one. Waypoints without their own
+//     speed fly the merged GlobalSpeed.
+//   - The other settings have no safer value and resolve to w's under both
+//     MergePolicyPreferFirst and MergePolicyPreferSafer.
+//
+// A setting left at its zero value in one mission is taken from the other
+// without a conflict. Under MergePolicyErrorOnConflict any other difference
+// is an error naming the field.
+func (w *Waylines) Append(next *Waylines, policy MergePolicy) (*Waylines, error) {
+	switch policy {
+	case MergePolicyErrorOnConflict, MergePolicyPreferFirst, MergePolicyPreferSafer:
+	default:
+		return nil, fmt.Errorf(ErrUnknownMergePolicy, policy)
+	}
+
+	m := &merger{policy: policy}
+	m.require("drone_model", w.DroneModel, next.DroneModel)
+	m.require("payload_model", w.PayloadModel, next.PayloadModel)
+	m.require("payload_position_index", w.PayloadPositionIndex, next.PayloadPositionIndex)
+	if !slices.Equal(w.AdditionalPayloads, next.AdditionalPayloads) {
+		m.fail("additional_payloads", w.AdditionalPayloads, next.AdditionalPayloads)
+	}
+	m.require("template_type", w.TemplateType, next.TemplateType)
+	m.require("height_type", w.heightMode(), next.heightMode())
+
+	merged := *w
+	merged.GlobalHeight = mergeField(m, "global_height", w.GlobalHeight, next.GlobalHeight, math.Max)
+	merged.GlobalSpeed = mergeField(m, "global_speed", w.GlobalSpeed, next.GlobalSpeed, math.Min)
+	merged.SafeHeight = mergeField(m, "safe_height", w.SafeHeight, next.SafeHeight, math.Max)
+	merged.GlobalRTHHeight = mergeField(m, "global_rth_height", w.GlobalRTHHeight, next.GlobalRTHHeight, math.Max)
+	merged.GlobalTransitionalSpeed = mergeField(m, "global_transitional_speed", w.GlobalTransitionalSpeed, next.GlobalTransitionalSpeed, math.Min)
+
+	merged.UseLowLightSmart = mergeField(m, "use_low_light_smart", w.UseLowLightSmart, next.UseLowLightSmart, nil)
+	merged.FinishAction = mergeField(m, "finish_action", w.FinishAction, next.FinishAction, nil)
+	merged.ExitOnRCLost = mergeField(m, "exit_on_rc_lost", w.ExitOnRCLost, next.ExitOnRCLost, nil)
+	merged.ExecuteRCLostAction = mergeField(m, "execute_rc_lost_action", w.ExecuteRCLostAction, next.ExecuteRCLostAction, nil)
+	merged.ClimbMode = mergeField(m, "climb_mode", w.ClimbMode, next.ClimbMode, nil)
+	merged.AircraftYawMode = mergeField(m, "aircraft_yaw_mode", w.AircraftYawMode, next.AircraftYawMode, nil)
+	merged.HeadingPOI = mergePointer(m, "heading_poi", w.HeadingPOI, next.HeadingPOI)
+	merged.HeadingPathMode = mergeField(m, "heading_path_mode", w.HeadingPathMode, next.HeadingPathMode, nil)
+	merged.GimbalPitchMode = mergeField(m, "gimbal_pitch_mode", w.GimbalPitchMode, next.GimbalPitchMode, nil)
+	merged.GlobalWaypointTurnMode = mergeField(m, "global_waypoint_turn_mode", w.GlobalWaypointTurnMode, next.GlobalWaypointTurnMode, nil)
+	merged.GlobalUseStraightLine = mergePointer(m, "global_use_straight_line", w.GlobalUseStraightLine, next.GlobalUseStraightLine)
+	merged.GlobalTurnDampingDist = mergeField(m, "global_turn_damping_dist", w.GlobalTurnDampingDist, next.GlobalTurnDampingDist, nil)
+	merged.WorkType = mergeField(m, "work_type", w.WorkType, next.WorkType, nil)
+	merged.WaypointEndType = mergeField(m, "waypoint_end_type", w.WaypointEndType, next.WaypointEndType, nil)
+	merged.LiDAR = mergePointer(m, "lidar", w.LiDAR, next.LiDAR)
+	merged.Mapping = mergePointer(m, "mapping", w.Mapping, next.Mapping)
+
+	merged.PhotoSettings = slices.Clone(w.PhotoSettings)
+	for _, lens := range next.PhotoSettings {
+		if !slices.Contains(merged.PhotoSettings, lens) {
+			merged.PhotoSettings = append(merged.PhotoSettings, lens)
+		}
+	}
+	merged.Metadata = m.mergeMetadata(w.Metadata, next.Metadata)
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	merged.Waypoints = make([]WaylinesWaypoint, 0, len(w.Waypoints)+len(next.Waypoints))
+	for _, wp := range slices.Concat(w.Waypoints, next.Waypoints) {
+		wp.Actions = slices.Clone(wp.Actions)
+		merged.Waypoints = append(merged.Waypoints, wp)
+	}
+	return &merged, nil
+}
+
+// merger records the first conflict found while merging two missions.
+type merger struct {
+	policy MergePolicy
+	err    error
+}
+
+func (m *merger) fail(field string, first, second any) {
+	if m.err == nil {
+		m.err = fmt.Errorf(ErrMergeConflict, field, first, second)
+	}
+}
+
+// require fails on any difference, whatever the policy.
+func (m *merger) require(field string, first, second any) {
+	if first != second {
+		m.fail(field, first, second)
+	}
+}
+
+// mergeField resolves one setting. safer picks the safer of two values, or is
+// nil when neither is safer.
+func mergeField[T comparable](m *merger, field string, first, second T, safer func(a, b T) T) T {
+	var zero T
+	switch {
+	case first == second, second == zero:
+		return first
+	case first == zero:
+		return second
+	}
+
+	switch m.policy {
+	case MergePolicyErrorOnConflict:
+		m.fail(field, first, second)
+	case MergePolicyPreferSafer:
+		if safer != nil {
+			return safer(first, second)
+		}
+	}
+	return first
+}
+
+// mergePointer resolves an optional setting, comparing the values pointed to.
+func mergePointer[T comparable](m *merger, field string, first, second *T) *T {
+	switch {
+	case second == nil:
+		return first
+	case first == nil:
+		return second
+	case *first != *second && m.policy == MergePolicyErrorOnConflict:
+		m.fail(field, *first, *second)
+	}
+	return first
+}
+
+func (m *merger) mergeMetadata(first, second map[string]string) map[string]string {
+	if len(first) == 0 && len(second) == 0 {
+		return nil
+	}
+	merged := maps.Clone(first)
+	if merged == nil {
+		merged = make(map[string]string, len(second))
+	}
+	// Sorted so the reported conflict does not depend on map order.
+	for _, key := range slices.Sorted(maps.Keys(second)) {
+		value := second[key]
+		existing, ok := merged[key]
+		switch {
+		case !ok:
+			merged[key] = value
+		case existing != value && m.policy == MergePolicyErrorOnConflict:
+			m.fail("metadata "+key, existing, value)
+		}
+	}
+	return merged
+}
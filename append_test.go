@@ -0,0 +1,136 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendSegments() (*Waylines, *Waylines) {
+	first := waylinesAt("Segment A", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	second := waylinesAt("Segment B", [2]float64{39.902, 116.400}, [2]float64{39.903, 116.400})
+	second.Waypoints[0].Actions = []ActionRequest{photoAction()}
+	return first, second
+}
+
+func TestAppend_MatchingSettings(t *testing.T) {
+	first, second := appendSegments()
+	second.Name = "Other planner"
+
+	merged, err := first.Append(second, MergePolicyErrorOnConflict)
+	require.NoError(t, err)
+	assert.Equal(t, "Segment A", merged.Name)
+	require.Len(t, merged.Waypoints, 4)
+	assert.Equal(t, 39.902, merged.Waypoints[2].Latitude)
+	assert.NoError(t, merged.Validate())
+
+	merged.Waypoints[2].Actions = nil
+	assert.Len(t, first.Waypoints, 2, "inputs are not modified")
+	assert.Len(t, second.Waypoints[0].Actions, 1, "inputs are not modified")
+}
+
+func TestAppend_Policies(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      MergePolicy
+		wantErr     string
+		rthHeight   float64
+		speed       float64
+		finish      FinishAction
+		metadataKey string
+	}{
+		{
+			name:    "Error on conflict",
+			policy:  MergePolicyErrorOnConflict,
+			wantErr: "global_speed differs: 15 and 8",
+		},
+		{
+			name:        "Prefer first",
+			policy:      MergePolicyPreferFirst,
+			rthHeight:   100,
+			speed:       15,
+			finish:      FinishActionGoHome,
+			metadataKey: "segment-a",
+		},
+		{
+			name:        "Prefer safer",
+			policy:      MergePolicyPreferSafer,
+			rthHeight:   150,
+			speed:       8,
+			finish:      FinishActionGoHome,
+			metadataKey: "segment-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := appendSegments()
+			first.GlobalSpeed = 15
+			first.GlobalRTHHeight = 100
+			first.FinishAction = FinishActionGoHome
+			first.Metadata = map[string]string{"planner": "segment-a"}
+			second.GlobalSpeed = 8
+			second.GlobalRTHHeight = 150
+			second.FinishAction = FinishActionAutoLand
+			second.Metadata = map[string]string{"planner": "segment-b", "corridor": "7"}
+
+			merged, err := first.Append(second, tt.policy)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rthHeight, merged.GlobalRTHHeight)
+			assert.Equal(t, tt.speed, merged.GlobalSpeed)
+			assert.Equal(t, tt.finish, merged.FinishAction)
+			assert.Equal(t, map[string]string{"planner": tt.metadataKey, "corridor": "7"}, merged.Metadata)
+		})
+	}
+}
+
+func TestAppend_UnsetSettingsDoNotConflict(t *testing.T) {
+	first, second := appendSegments()
+	first.ExitOnRCLost = RCLostActionExecuteLostAction
+	first.PhotoSettings = []string{"wide"}
+	second.PhotoSettings = []string{"zoom", "wide"}
+	second.Mapping = &MappingConfig{FrontOverlap: 80, SideOverlap: 70}
+
+	merged, err := first.Append(second, MergePolicyErrorOnConflict)
+	require.NoError(t, err)
+	assert.Equal(t, RCLostActionExecuteLostAction, merged.ExitOnRCLost)
+	assert.Equal(t, []string{"wide", "zoom"}, merged.PhotoSettings)
+	assert.Equal(t, second.Mapping, merged.Mapping)
+}
+
+func TestAppend_RequiredMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(w *Waylines)
+		wantErr string
+	}{
+		{name: "Drone model", modify: func(w *Waylines) { w.DroneModel = DroneM300RTK }, wantErr: "drone_model"},
+		{name: "Template type", modify: func(w *Waylines) { w.TemplateType = TemplateTypeMapping2D }, wantErr: "template_type"},
+		{name: "Height type", modify: func(w *Waylines) { w.HeightType = HeightModeEGM96 }, wantErr: "height_type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := appendSegments()
+			tt.modify(second)
+
+			for _, policy := range []MergePolicy{MergePolicyErrorOnConflict, MergePolicyPreferFirst, MergePolicyPreferSafer} {
+				_, err := first.Append(second, policy)
+				require.Error(t, err, policy)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAppend_UnknownPolicy(t *testing.T) {
+	first, second := appendSegments()
+	_, err := first.Append(second, "preferLast")
+	assert.EqualError(t, err, `unknown merge policy "preferLast"`)
+}
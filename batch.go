@@ -0,0 +1,104 @@
+package wpml
+
+import (
+	"runtime"
+	"sync"
+)
+
+// BatchOptions controls ConvertBatch.
+type BatchOptions struct {
+	// Workers is the number of missions converted concurrently. Defaults to
+	// runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Render is applied to every mission. Its Progress callback is not used;
+	// set Progress below to follow the batch. Its ActionGroupIDAllocator and
+	// IDSource must be nil: shared by the workers, the IDs each mission got
+	// would depend on goroutine timing. Set ActionGroupIDAllocator or
+	// IDSource below instead.
+	Render RenderOptions
+
+	// ActionGroupIDAllocator, if set, returns the allocator for the mission
+	// at index. Each mission defaults to its own
+	// MonotonicActionGroupIDAllocator.
+	ActionGroupIDAllocator func(index int) ActionGroupIDAllocator
+
+	// IDSource, if set, returns the ID source for the mission at index, so
+	// that every mission gets the same action group IDs however the batch is
+	// scheduled. It cannot be combined with ActionGroupIDAllocator.
+	IDSource func(index int) func() int
+
+	// Progress, if set, is called once per finished mission with the number
+	// of missions finished so far. It is called from the worker goroutines,
+	// but calls are serialized: it never runs concurrently with itself and
+	// completed is strictly increasing, so it needs no locking of its own. It
+	// should return quickly, as workers wait for it.
+	Progress ProgressFunc
+}
+
+// BatchResult is the outcome of converting one mission of a batch.
+type BatchResult struct {
+	Mission *WPMLMission
+	Err     error
+}
+
+// ConvertBatch converts missions concurrently. Results are returned in the
+// order of the input; a failed conversion does not stop the others. Options
+// that would share action group IDs between missions fail every result with
+// ErrBatchSharedIDSource.
+func ConvertBatch(missions []*Waylines, opts BatchOptions) []BatchResult {
+	results := make([]BatchResult, len(missions))
+	if opts.Render.ActionGroupIDAllocator != nil || opts.Render.IDSource != nil {
+		for i := range results {
+			results[i].Err = ErrBatchSharedIDSource
+		}
+		return results
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(missions))
+
+	render := opts.Render
+	render.Progress = nil
+
+	var mu sync.Mutex
+	progress := newProgressTracker(opts.Progress, len(missions))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				mission, err := ConvertWaylinesToWPMLMissionWithOptions(missions[i], opts.missionRender(render, i))
+				results[i] = BatchResult{Mission: mission, Err: err}
+
+				mu.Lock()
+				progress.step()
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range missions {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func (o BatchOptions) missionRender(render RenderOptions, index int) RenderOptions {
+	if o.ActionGroupIDAllocator != nil {
+		render.ActionGroupIDAllocator = o.ActionGroupIDAllocator(index)
+	}
+	if o.IDSource != nil {
+		render.IDSource = o.IDSource(index)
+	}
+	return render
+}
@@ -0,0 +1,108 @@
+package wpml
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertBatch(t *testing.T) {
+	missions := []*Waylines{
+		waylinesWithActionsAt("Batch 1", 1),
+		waylinesWithActionsAt("Batch 2", 2),
+		{Name: "Invalid"},
+		waylinesWithActionsAt("Batch 4", 4),
+	}
+
+	var calls [][2]int
+	results := ConvertBatch(missions, BatchOptions{
+		Workers: 3,
+		Progress: func(completed, total int) {
+			calls = append(calls, [2]int{completed, total})
+		},
+	})
+
+	require.Len(t, results, len(missions))
+	for i, result := range results {
+		if i == 2 {
+			assert.Error(t, result.Err)
+			assert.Nil(t, result.Mission)
+			continue
+		}
+		require.NoError(t, result.Err)
+		assert.Len(t, result.Mission.Waylines.Document.Folders[0].Placemarks, len(missions[i].Waypoints), "results keep input order")
+	}
+
+	assert.Equal(t, [][2]int{{1, 4}, {2, 4}, {3, 4}, {4, 4}}, calls)
+}
+
+func TestConvertBatch_Empty(t *testing.T) {
+	assert.Empty(t, ConvertBatch(nil, BatchOptions{}))
+}
+
+func TestConvertBatch_ReproducibleIDs(t *testing.T) {
+	missions := make([]*Waylines, 8)
+	for i := range missions {
+		missions[i] = waylinesWithActionsAt("Batch", 3, 0, 1, 2)
+	}
+	renderTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	render := func() [][]byte {
+		results := ConvertBatch(missions, BatchOptions{
+			Workers: 4,
+			Render:  RenderOptions{Clock: func() time.Time { return renderTime }},
+			IDSource: func(index int) func() int {
+				next := index * 100
+				return func() int {
+					next++
+					return next
+				}
+			},
+		})
+		archives := make([][]byte, len(results))
+		for i, result := range results {
+			require.NoError(t, result.Err)
+			var buf bytes.Buffer
+			require.NoError(t, WriteKmz(result.Mission, &buf))
+			archives[i] = buf.Bytes()
+		}
+		return archives
+	}
+
+	first := render()
+	assert.Equal(t, first, render())
+
+	groups := ConvertBatch(missions[5:6], BatchOptions{IDSource: func(int) func() int {
+		next := 500
+		return func() int {
+			next++
+			return next
+		}
+	}})[0].Mission.Waylines.Document.Folders[0].Placemarks[0].ActionGroups
+	assert.Equal(t, 501, groups[0].ActionGroupID)
+}
+
+func TestConvertBatch_SharedIDSource(t *testing.T) {
+	missions := []*Waylines{waylinesWithActionsAt("Batch 1", 1, 0), waylinesWithActionsAt("Batch 2", 2, 0)}
+
+	for _, render := range []RenderOptions{
+		{IDSource: func() int { return 1 }},
+		{ActionGroupIDAllocator: NewMonotonicActionGroupIDAllocator()},
+	} {
+		for _, result := range ConvertBatch(missions, BatchOptions{Render: render}) {
+			assert.ErrorIs(t, result.Err, ErrBatchSharedIDSource)
+			assert.Nil(t, result.Mission)
+		}
+	}
+
+	results := ConvertBatch(missions, BatchOptions{
+		ActionGroupIDAllocator: func(int) ActionGroupIDAllocator { return NewMonotonicActionGroupIDAllocator() },
+	})
+	for _, result := range results {
+		require.NoError(t, result.Err)
+		assert.Equal(t, 0, result.Mission.Waylines.Document.Folders[0].Placemarks[0].ActionGroups[0].ActionGroupID)
+	}
+}
@@ -0,0 +1,148 @@
+package wpml
+
+// DroneLimits describes the flight envelope of an aircraft that mission
+// generators and rules respect.
+type DroneLimits struct {
+	// MinHeight is the lowest waypoint height, in meters above the takeoff
+	// point, the aircraft accepts in relative height mode.
+	MinHeight float64
+	// MaxSpeed is the highest speed, in m/s, the aircraft flies in a
+	// waypoint mission. WPML caps waypoint speeds at 15 m/s on every
+	// supported aircraft.
+	MaxSpeed float64
+	// MaxAscentSpeed and MaxDescentSpeed are the highest vertical speeds, in
+	// m/s, the aircraft climbs and descends at.
+	MaxAscentSpeed  float64
+	MaxDescentSpeed float64
+}
+
+var defaultDroneLimits = DroneLimits{
+	MinHeight:       5,
+	MaxSpeed:        15,
+	MaxAscentSpeed:  6,
+	MaxDescentSpeed: 5,
+}
+
+var droneLimits = map[DroneModel]DroneLimits{
+	DroneM300RTK:   {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 5},
+	DroneM350RTK:   {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 5},
+	DroneM30:       {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 5},
+	DroneM3Series:  {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 6},
+	DroneM3DSeries: {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 6},
+	DroneM4Series:  {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 5},
+	DroneM4DSeries: {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 5},
+	DroneM400:      {MinHeight: 5, MaxSpeed: 15, MaxAscentSpeed: 6, MaxDescentSpeed: 5},
+}
+
+// LimitsForDrone returns the limits for droneModel, falling back to
+// conservative defaults for unknown models.
+func LimitsForDrone(droneModel DroneModel) DroneLimits {
+	if limits, ok := droneLimits[droneModel]; ok {
+		return limits
+	}
+	return defaultDroneLimits
+}
+
+// dronePayloadPositions lists the payload positions of each aircraft. The
+// M300 RTK class carries up to three gimbals plus the PSDK port; the other
+// aircraft have a single integrated camera.
+var dronePayloadPositions = map[DroneModel][]PayloadPosition{
+	DroneM300RTK:   {PayloadPosition0, PayloadPosition1, PayloadPosition2, PayloadPosition7},
+	DroneM350RTK:   {PayloadPosition0, PayloadPosition1, PayloadPosition2, PayloadPosition7},
+	DroneM400:      {PayloadPosition0, PayloadPosition1, PayloadPosition2, PayloadPosition7},
+	DroneM30:       {PayloadPosition0},
+	DroneM3Series:  {PayloadPosition0},
+	DroneM3DSeries: {PayloadPosition0},
+	DroneM4Series:  {PayloadPosition0},
+	DroneM4DSeries: {PayloadPosition0},
+}
+
+// PayloadPositionsForDrone returns the payload positions of droneModel. ok is
+// false for unknown models.
+func PayloadPositionsForDrone(droneModel DroneModel) (positions []PayloadPosition, ok bool) {
+	positions, ok = dronePayloadPositions[droneModel]
+	return positions, ok
+}
+
+type AngleRange struct {
+	Min float64
+	Max float64
+}
+
+func (r AngleRange) Contains(angle float64) bool {
+	return angle >= r.Min && angle <= r.Max
+}
+
+// containsDirection reports whether the range holds angle or any angle a full
+// turn away from it, all of which point the same way.
+func (r AngleRange) containsDirection(angle float64) bool {
+	angle = normalizeAngle(angle)
+	return r.Contains(angle) || r.Contains(angle-360) || r.Contains(angle+360)
+}
+
+// PayloadCapabilities describes the physical limits of a payload that the
+// mission rules check against. A nil range means the axis is not mechanically
+// controllable (the aircraft rotates instead) or its limit is unknown.
+type PayloadCapabilities struct {
+	GimbalYawRange *AngleRange
+	// LiDAR is set for payloads that record point clouds.
+	LiDAR *LiDARCapabilities
+	// Positions lists the payload positions the payload can be mounted at.
+	// Integrated cameras only occupy the main position.
+	Positions []PayloadPosition
+}
+
+// LiDARCapabilities lists the point-cloud settings a LiDAR payload accepts.
+type LiDARCapabilities struct {
+	ReturnModes   []string
+	ScanningModes []string
+	SamplingRates []int
+}
+
+var (
+	l1Capabilities = LiDARCapabilities{
+		ReturnModes:   []string{LiDARReturnModeSingleStrongest, LiDARReturnModeDual, LiDARReturnModeTriple},
+		ScanningModes: []string{LiDARScanningModeRepetitive, LiDARScanningModeNonRepetitive},
+		SamplingRates: []int{160000, 240000},
+	}
+	l2Capabilities = LiDARCapabilities{
+		ReturnModes:   []string{LiDARReturnModeSingleStrongest, LiDARReturnModeDual, LiDARReturnModeTriple},
+		ScanningModes: []string{LiDARScanningModeRepetitive, LiDARScanningModeNonRepetitive},
+		SamplingRates: []int{60000, 80000, 120000, 160000, 180000, 240000},
+	}
+)
+
+var (
+	gimbalPositions     = []PayloadPosition{PayloadPosition0, PayloadPosition1, PayloadPosition2}
+	integratedPositions = []PayloadPosition{PayloadPosition0}
+)
+
+var payloadCapabilities = map[PayloadModel]PayloadCapabilities{
+	PayloadZ30:        {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadXT2:        {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadXTS:        {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadH20:        {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadH20T:       {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadH20N:       {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadH30:        {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadH30T:       {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, Positions: gimbalPositions},
+	PayloadM30Camera:  {GimbalYawRange: &AngleRange{Min: -90, Max: 90}, Positions: integratedPositions},
+	PayloadM30TCamera: {GimbalYawRange: &AngleRange{Min: -90, Max: 90}, Positions: integratedPositions},
+	PayloadL1:         {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, LiDAR: &l1Capabilities, Positions: gimbalPositions},
+	PayloadL2:         {GimbalYawRange: &AngleRange{Min: -320, Max: 320}, LiDAR: &l2Capabilities, Positions: gimbalPositions},
+
+	PayloadMavic3ECamera:    {Positions: integratedPositions},
+	PayloadMavic3TCamera:    {Positions: integratedPositions},
+	PayloadMavic3ACamera:    {Positions: integratedPositions},
+	PayloadMatrice3DCamera:  {Positions: integratedPositions},
+	PayloadMatrice3TDCamera: {Positions: integratedPositions},
+	PayloadMatrice4ECamera:  {Positions: integratedPositions},
+	PayloadMatrice4TCamera:  {Positions: integratedPositions},
+	PayloadMatrice4DCamera:  {Positions: integratedPositions},
+	PayloadMatrice4TDCamera: {Positions: integratedPositions},
+}
+
+func CapabilitiesForPayload(payloadModel PayloadModel) (PayloadCapabilities, bool) {
+	capabilities, ok := payloadCapabilities[payloadModel]
+	return capabilities, ok
+}
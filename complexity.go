@@ -0,0 +1,88 @@
+package wpml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MissionComplexity measures how much a mission asks of the controller when it
+// is uploaded and parsed.
+type MissionComplexity struct {
+	Waypoints    int `json:"waypoints"`
+	ActionGroups int `json:"action_groups"`
+	// Actions counts the actions the converter emits, including the
+	// stabilization hovers of stop-and-go missions.
+	Actions int `json:"actions"`
+	// Score is the number of waypoints plus the number of actions, the
+	// elements the controller parses on upload. Each waypoint and each
+	// action adds one.
+	Score float64 `json:"score"`
+}
+
+// AverageActions returns the mean number of actions per waypoint.
+func (c MissionComplexity) AverageActions() float64 {
+	if c.Waypoints == 0 {
+		return 0
+	}
+	return float64(c.Actions) / float64(c.Waypoints)
+}
+
+// ComplexityThresholds are the limits above which a mission risks slow parsing
+// on the controller or upload timeouts. A zero threshold is not checked.
+type ComplexityThresholds struct {
+	MaxWaypoints int     `json:"max_waypoints"`
+	MaxScore     float64 `json:"max_score"`
+}
+
+// DefaultComplexityThresholds are the limits Warnings checks. They are a
+// heuristic: missions of around 10,000 waypoints with an action at each took
+// minutes to upload and sometimes failed.
+var DefaultComplexityThresholds = ComplexityThresholds{
+	MaxWaypoints: 5000,
+	MaxScore:     10000,
+}
+
+// Complexity returns the complexity of the mission.
+func (w *Waylines) Complexity() MissionComplexity {
+	c := MissionComplexity{Waypoints: len(w.Waypoints), ActionGroups: w.ActionGroupCount()}
+	for i := range w.Waypoints {
+		for _, group := range w.actionGroups(i) {
+			c.Actions += len(group.actions)
+		}
+	}
+	c.Score = float64(c.Waypoints + c.Actions)
+	return c
+}
+
+// ValidateComplexity returns an error when the mission exceeds thresholds.
+// Pass DefaultComplexityThresholds for the limits Warnings reports.
+func (w *Waylines) ValidateComplexity(thresholds ComplexityThresholds) error {
+	if messages := w.complexityFindings(thresholds); len(messages) > 0 {
+		return errors.New(messages[0])
+	}
+	return nil
+}
+
+func complexityWarnings(w *Waylines) []Warning {
+	var warnings []Warning
+	for _, message := range w.complexityFindings(DefaultComplexityThresholds) {
+		warnings = append(warnings, Warning{
+			Rule:    WarningRuleMissionComplexity,
+			Message: message,
+		})
+	}
+	return warnings
+}
+
+func (w *Waylines) complexityFindings(thresholds ComplexityThresholds) []string {
+	c := w.Complexity()
+
+	var messages []string
+	if thresholds.MaxWaypoints > 0 && c.Waypoints > thresholds.MaxWaypoints {
+		messages = append(messages, fmt.Sprintf(ErrTooManyWaypointsForUpload, c.Waypoints, thresholds.MaxWaypoints))
+	}
+	if thresholds.MaxScore > 0 && c.Score > thresholds.MaxScore {
+		messages = append(messages, fmt.Sprintf(ErrMissionComplexityTooHigh, c.Score, c.Waypoints, c.Actions, thresholds.MaxScore))
+	}
+	return messages
+}
@@ -0,0 +1,51 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplexity(t *testing.T) {
+	waylines := waylinesWithActionsAt("Complexity", 4, 0, 2)
+	waylines.Waypoints[2].Actions = append(waylines.Waypoints[2].Actions, photoAction())
+
+	c := waylines.Complexity()
+	assert.Equal(t, 4, c.Waypoints)
+	assert.Equal(t, 2, c.ActionGroups)
+	assert.Equal(t, 3, c.Actions)
+	assert.Equal(t, 0.75, c.AverageActions())
+	assert.Equal(t, 7.0, c.Score)
+
+	assert.Zero(t, (&Waylines{}).Complexity().Score)
+}
+
+func TestValidateComplexity(t *testing.T) {
+	waylines := waylinesWithActionsAt("Complexity", 10, 0, 1, 2, 3, 4)
+	assert.NoError(t, waylines.ValidateComplexity(DefaultComplexityThresholds))
+	assert.Empty(t, complexityWarnings(waylines))
+
+	err := waylines.ValidateComplexity(ComplexityThresholds{MaxScore: 12})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "complexity score 15 (10 waypoints plus 5 actions)")
+
+	err = waylines.ValidateComplexity(ComplexityThresholds{MaxWaypoints: 8})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mission has 10 waypoints, above the threshold of 8")
+
+	assert.NoError(t, waylines.ValidateComplexity(ComplexityThresholds{}), "zero thresholds are not checked")
+}
+
+func TestComplexityWarnings(t *testing.T) {
+	waylines := waylinesWithActionsAt("Large", DefaultComplexityThresholds.MaxWaypoints+1)
+	for i := range waylines.Waypoints {
+		waylines.Waypoints[i].Actions = []ActionRequest{photoAction()}
+	}
+
+	warnings := complexityWarnings(waylines)
+	require.Len(t, warnings, 2)
+	for _, warning := range warnings {
+		assert.Equal(t, WarningRuleMissionComplexity, warning.Rule)
+	}
+}
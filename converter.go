@@ -1,13 +1,28 @@
 package wpml
 
-import (
-	"fmt"
-	"math"
-)
+import "fmt"
 
 const DefaultAuthor = "DJI WPML SDK"
 
 func ConvertWaylinesToWPMLMission(waylines *Waylines) (*WPMLMission, error) {
+	return ConvertWaylinesToWPMLMissionWithOptions(waylines, RenderOptions{})
+}
+
+func ConvertWaylinesToWPMLMissionWithOptions(waylines *Waylines, opts RenderOptions) (*WPMLMission, error) {
+	return convertWaylinesToWPMLMission(waylines, opts, newProgressTracker(opts.Progress, renderSteps(waylines)))
+}
+
+// renderSteps is the number of progress steps converting waylines takes: one
+// per placemark in each of the template and wayline folders.
+func renderSteps(waylines *Waylines) int {
+	return 2 * len(waylines.Waypoints)
+}
+
+func convertWaylinesToWPMLMission(waylines *Waylines, opts RenderOptions, progress *progressTracker) (*WPMLMission, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf(ErrInvalidRenderOptions, err)
+	}
+
 	waylines.ApplyDefaults()
 
 	if err := waylines.Validate(); err != nil {
@@ -15,21 +30,30 @@ func ConvertWaylinesToWPMLMission(waylines *Waylines) (*WPMLMission, error) {
 	}
 
 	mission := NewWPMLMission()
+	mission.HeaderComment = opts.HeaderComment
 	mission.SetAuthor(DefaultAuthor)
-	mission.UpdateTimestamp()
+	renderTime := opts.now().UnixMilli()
+	mission.Template.Document.CreateTime = renderTime
+	mission.Template.Document.UpdateTime = renderTime
+	mission.SetMetadata(waylines.Metadata)
 	missionConfig, err := convertToMissionConfig(waylines)
 	if err != nil {
 		return nil, fmt.Errorf(ErrConvertMissionConfig, err)
 	}
 	mission.SetMissionConfig(*missionConfig)
 
-	templateFolder, err := convertToTemplateFolder(waylines)
+	actionGroupIDs, err := allocateActionGroupIDs(waylines, opts.actionGroupIDAllocator())
+	if err != nil {
+		return nil, fmt.Errorf(ErrAllocateActionGroupIDs, err)
+	}
+
+	templateFolder, err := convertToTemplateFolder(waylines, actionGroupIDs, opts.targetVersion(), progress)
 	if err != nil {
 		return nil, fmt.Errorf(ErrConvertTemplateFolder, err)
 	}
 	mission.Template.Document.Folders = []TemplateFolder{*templateFolder}
 
-	waylineFolder, err := convertToWaylineFolder(waylines)
+	waylineFolder, err := convertToWaylineFolder(waylines, actionGroupIDs, opts.targetVersion(), progress)
 	if err != nil {
 		return nil, fmt.Errorf(ErrConvertWaylineFolder, err)
 	}
@@ -38,26 +62,46 @@ func ConvertWaylinesToWPMLMission(waylines *Waylines) (*WPMLMission, error) {
 	return mission, nil
 }
 
+// allocateActionGroupIDs allocates the action group IDs of every waypoint and
+// rejects IDs from the allocator that wpml:actionGroupId cannot hold or that
+// another group of the wayline already uses.
+func allocateActionGroupIDs(waylines *Waylines, allocator ActionGroupIDAllocator) (map[int][]int, error) {
+	ids := make(map[int][]int)
+	usedBy := make(map[int]int)
+	for i, wp := range waylines.Waypoints {
+		for _, group := range waylines.actionGroups(i) {
+			id := allocator.AllocateActionGroupID(ActionGroupSite{
+				WaypointIndex: i,
+				Position:      wp.position(),
+				TriggerType:   group.trigger.ActionTriggerType,
+			})
+			if id < 0 || id > maxActionGroupID {
+				return nil, fmt.Errorf(ErrActionGroupIDOutOfRange, i, id, maxActionGroupID)
+			}
+			if other, ok := usedBy[id]; ok {
+				return nil, fmt.Errorf(ErrDuplicateActionGroupID, i, id, other)
+			}
+			usedBy[id] = i
+			ids[i] = append(ids[i], id)
+		}
+	}
+	return ids, nil
+}
+
 func convertToMissionConfig(waylines *Waylines) (*MissionConfig, error) {
 
 	flyToWaylineMode := FlightModeSafely
-	finishAction := FinishActionGoHome
-	if waylines.FinishAction != "" {
-		finishAction = waylines.FinishAction
-	}
+	finishAction := waylines.finishAction()
 
 	exitOnRCLost := RCLostActionGoContinue
-	executeRCLostAction := ExecuteRCLostActionHover
-
-	takeOffSecurityHeight := waylines.SafeHeight
-	if takeOffSecurityHeight == 0 {
-		takeOffSecurityHeight = 20
+	if waylines.ExitOnRCLost != "" {
+		exitOnRCLost = waylines.ExitOnRCLost
 	}
+	executeRCLostAction := waylines.executeRCLostAction()
 
-	globalTransitionalSpeed := waylines.GlobalTransitionalSpeed
-	if globalTransitionalSpeed == 0 {
-		globalTransitionalSpeed = 6
-	}
+	takeOffSecurityHeight := waylines.takeOffSecurityHeight()
+
+	globalTransitionalSpeed := waylines.transitionalSpeed()
 
 	globalRTHHeight := 100.0
 	if waylines.GlobalRTHHeight > 0 {
@@ -81,7 +125,7 @@ func convertToMissionConfig(waylines *Waylines) (*MissionConfig, error) {
 	var takeOffRefPoint *string
 	var takeOffRefPointAGLHeight *float64
 
-	if waylines.TakeOffRefPointLatitude != 0 && waylines.TakeOffRefPointLongitude != 0 {
+	if waylines.hasTakeOffRefPoint() {
 
 		takeOffPointStr := fmt.Sprintf("%.6f,%.6f,%.1f",
 			waylines.TakeOffRefPointLatitude,
@@ -120,7 +164,7 @@ func convertToMissionConfig(waylines *Waylines) (*MissionConfig, error) {
 	}, nil
 }
 
-func convertToTemplateFolder(waylines *Waylines) (*TemplateFolder, error) {
+func convertToTemplateFolder(waylines *Waylines, actionGroupIDs map[int][]int, version WPMLVersion, progress *progressTracker) (*TemplateFolder, error) {
 
 	heightMode := HeightModeRelativeToStartPoint
 	if waylines.HeightType != "" {
@@ -153,40 +197,47 @@ func convertToTemplateFolder(waylines *Waylines) (*TemplateFolder, error) {
 
 	placemarks := make([]Placemark, len(waylines.Waypoints))
 	for i, wp := range waylines.Waypoints {
-		placemark, err := convertToTemplatePlacemark(wp, i)
+		placemark, err := convertToTemplatePlacemark(wp, i, actionGroupIDs[i], waylines, version)
 		if err != nil {
 			return nil, fmt.Errorf(ErrConvertWaypoint, i, err)
 		}
 		placemarks[i] = *placemark
+		progress.step()
 	}
 
-	return &TemplateFolder{
+	folder := &TemplateFolder{
 		TemplateType:               waylines.TemplateType,
 		TemplateID:                 0,
 		AutoFlightSpeed:            waylines.GlobalSpeed,
 		GlobalHeight:               &waylines.GlobalHeight,
 		WaylineCoordinateSysParam:  waylineCoordSysParam,
+		PayloadParams:              convertToPayloadParams(waylines),
+		Overlap:                    convertToOverlap(waylines),
 		GimbalPitchMode:            stringPtr(waylines.GimbalPitchMode),
 		GlobalWaypointHeadingParam: convertGlobalHeadingParam(waylines),
 		Placemarks:                 placemarks,
-	}, nil
+	}
+	folder.syncLegacyPayloadParam()
+	return folder, nil
 }
 
-func convertToWaylineFolder(waylines *Waylines) (*WaylineFolder, error) {
+func convertToWaylineFolder(waylines *Waylines, actionGroupIDs map[int][]int, version WPMLVersion, progress *progressTracker) (*WaylineFolder, error) {
 	executeHeightMode := ExecuteHeightModeRelativeToStartPoint
 	if waylines.HeightType == HeightModeRealTimeFollowSurface {
 		executeHeightMode = ExecuteHeightModeRealTimeFollowSurface
 	}
 	placemarks := make([]Placemark, 0, len(waylines.Waypoints))
 	for i, wp := range waylines.Waypoints {
-		placemark, err := convertToWaylinePlacemark(wp, i, waylines)
+		placemark, err := convertToWaylinePlacemark(wp, i, actionGroupIDs[i], waylines, version)
 		if err != nil {
 			return nil, fmt.Errorf(ErrConvertWaypoint, i, err)
 		}
 		placemarks = append(placemarks, *placemark)
+		progress.step()
 	}
 
-	distance, duration := calculateWaylineStats(waylines.Waypoints, waylines.GlobalSpeed)
+	distance := waylines.flightDistance()
+	duration := waylines.EstimatedDuration().Seconds()
 
 	return &WaylineFolder{
 		TemplateID:        0,
@@ -199,51 +250,49 @@ func convertToWaylineFolder(waylines *Waylines) (*WaylineFolder, error) {
 	}, nil
 }
 
-func convertToTemplatePlacemark(waypoint WaylinesWaypoint, index int) (*Placemark, error) {
+func convertToTemplatePlacemark(waypoint WaylinesWaypoint, index int, actionGroupIDs []int, waylines *Waylines, version WPMLVersion) (*Placemark, error) {
 	point := &Point{
 		Coordinates: formatCoordinates(waypoint.Longitude, waypoint.Latitude),
 	}
 
-	ellipsoidHeight := waypoint.Height
-	height := waypoint.Height
+	missionHeight, err := waylines.missionHeight(waypoint)
+	if err != nil {
+		return nil, err
+	}
+	ellipsoidHeight := missionHeight
+	height := missionHeight
 
 	var waypointSpeed *float64
 	if waypoint.Speed > 0 {
 		waypointSpeed = &waypoint.Speed
 	}
 
+	// Every waypoint carries its own height: zero is a valid EGM96 height,
+	// not a request for the global one.
 	useGlobalHeight := 0
-	if waypoint.Height == 0 {
-		useGlobalHeight = 1
-	}
 
 	useGlobalSpeed := 0
 	if waypoint.Speed == 0 {
 		useGlobalSpeed = 1
 	}
 
-	headingParam := &WaypointHeadingParam{
-		WaypointHeadingMode:        HeadingModeFollowWayline,
-		WaypointHeadingAngle:       float64Ptr(0),
-		WaypointPoiPoint:           stringPtr("0.000000,0.000000,0.000000"),
-		WaypointHeadingAngleEnable: intPtr(0),
-		WaypointHeadingPathMode:    "followBadArc",
-		WaypointHeadingPoiIndex:    intPtr(0),
-	}
+	headingParam := convertWaypointHeadingParam(waylines, waypoint)
 
-	turnParam := &WaypointTurnParam{
-		WaypointTurnMode:        TurnModeToPointAndStopWithContinuityCurvature,
-		WaypointTurnDampingDist: float64Ptr(0.2),
+	useGlobalHeadingParam := 1
+	if waypoint.HeadingPathMode != "" {
+		useGlobalHeadingParam = 0
 	}
 
-	var actionGroups []ActionGroup
-	if len(waypoint.Actions) > 0 {
-		actionGroup := convertToActionGroup(waypoint.Actions, waypoint.TriggerType, index)
-		if actionGroup != nil {
-			actionGroups = append(actionGroups, *actionGroup)
-		}
+	turnParam := templateTurnParam(version)
+
+	useGlobalTurnParam := 1
+	if index == len(waylines.Waypoints)-1 && waylines.WaypointEndType != "" {
+		turnParam.WaypointTurnMode = waylines.turnModeAt(index)
+		useGlobalTurnParam = 0
 	}
 
+	actionGroups := convertToActionGroups(waylines, index, actionGroupIDs)
+
 	return &Placemark{
 		Point:                 point,
 		Index:                 index,
@@ -254,52 +303,45 @@ func convertToTemplatePlacemark(waypoint WaylinesWaypoint, index int) (*Placemar
 		WaypointSpeed:         waypointSpeed,
 		WaypointHeadingParam:  headingParam,
 		WaypointTurnParam:     turnParam,
-		UseGlobalHeadingParam: intPtr(1),
-		UseGlobalTurnParam:    intPtr(1),
+		UseGlobalHeadingParam: intPtr(useGlobalHeadingParam),
+		UseGlobalTurnParam:    intPtr(useGlobalTurnParam),
 		UseStraightLine:       intPtr(1),
 		ActionGroups:          actionGroups,
 		IsRisky:               intPtr(0),
-		WaypointWorkType:      intPtr(0),
+		WaypointWorkType:      intPtr(waylines.waypointWorkType()),
 	}, nil
 }
 
-func convertToWaylinePlacemark(waypoint WaylinesWaypoint, index int, waylines *Waylines) (*Placemark, error) {
+func convertToWaylinePlacemark(waypoint WaylinesWaypoint, index int, actionGroupIDs []int, waylines *Waylines, version WPMLVersion) (*Placemark, error) {
 	point := &Point{
 		Coordinates: formatCoordinates(waypoint.Longitude, waypoint.Latitude),
 	}
 
-	executeHeight := waypoint.Height
+	executeHeight, err := waylines.missionHeight(waypoint)
+	if err != nil {
+		return nil, err
+	}
 
 	speed := waypoint.Speed
 	if speed == 0 {
 		speed = waylines.GlobalSpeed
 	}
 
-	headingParam := &WaypointHeadingParam{
-		WaypointHeadingMode:        HeadingModeFollowWayline,
-		WaypointHeadingAngle:       float64Ptr(0),
-		WaypointPoiPoint:           stringPtr("0.000000,0.000000,0.000000"),
-		WaypointHeadingAngleEnable: intPtr(0),
-		WaypointHeadingPathMode:    "followBadArc",
-		WaypointHeadingPoiIndex:    intPtr(0),
-	}
+	headingParam := convertWaypointHeadingParam(waylines, waypoint)
 
 	gimbalHeadingParam := &WaypointGimbalHeadingParam{
 		WaypointGimbalPitchAngle: float64Ptr(0),
 		WaypointGimbalYawAngle:   float64Ptr(0),
 	}
 
-	turnParam := createWaypointTurnParam(waypoint, waylines)
+	turnParam, err := createWaypointTurnParam(waypoint, index, waylines, version)
+	if err != nil {
+		return nil, err
+	}
 
 	useStraightLine := getUseStraightLine(waypoint, waylines, turnParam.WaypointTurnMode)
 
-	var actionGroups []ActionGroup
-	if len(waypoint.Actions) > 0 {
-		actionGroup := convertToActionGroup(waypoint.Actions, waypoint.TriggerType, index)
-		if actionGroup != nil {
-			actionGroups = append(actionGroups, *actionGroup)
-		}
-	}
+	actionGroups := convertToActionGroups(waylines, index, actionGroupIDs)
 
 	return &Placemark{
 		Point:                      point,
@@ -312,33 +354,27 @@ func convertToWaylinePlacemark(waypoint WaylinesWaypoint, index int, waylines *W
 		ActionGroups:               actionGroups,
 		WaypointGimbalHeadingParam: gimbalHeadingParam,
 		IsRisky:                    intPtr(0),
-		WaypointWorkType:           intPtr(0),
+		WaypointWorkType:           intPtr(waylines.waypointWorkType()),
 	}, nil
 }
 
-func convertToActionGroup(actions []ActionRequest, triggerType string, waypointIndex int) *ActionGroup {
-	if len(actions) == 0 {
-		return nil
-	}
-
-	triggerTypeStr := TriggerTypeReachPoint
-	switch triggerType {
-	case "reachPoint":
-		triggerTypeStr = TriggerTypeReachPoint
-	case "passPoint":
-		triggerTypeStr = TriggerTypePassPoint
-	case "manual":
-		triggerTypeStr = TriggerTypeManual
-	case "betweenAdjacentPoints":
-		triggerTypeStr = TriggerTypeBetweenAdjacentPoints
-	case "multipleTiming":
-		triggerTypeStr = TriggerTypeMultipleTiming
-	case "multipleDistance":
-		triggerTypeStr = TriggerTypeMultipleDistance
+// convertToActionGroups converts the action groups of waypoint index, giving
+// them the allocated IDs in order.
+func convertToActionGroups(waylines *Waylines, index int, actionGroupIDs []int) []ActionGroup {
+	var actionGroups []ActionGroup
+	for i, group := range waylines.actionGroups(index) {
+		endIndex := waylines.actionGroupEndIndex(index, group.trigger)
+		actionGroup := convertToActionGroup(group.actions, group.trigger, index, endIndex, actionGroupIDs[i])
+		if actionGroup != nil {
+			actionGroups = append(actionGroups, *actionGroup)
+		}
 	}
+	return actionGroups
+}
 
-	trigger := ActionTrigger{
-		ActionTriggerType: triggerTypeStr,
+func convertToActionGroup(actions []ActionRequest, trigger ActionTrigger, waypointIndex, endIndex int, actionGroupID int) *ActionGroup {
+	if len(actions) == 0 {
+		return nil
 	}
 
 	actionList := make([]Action, len(actions))
@@ -351,9 +387,9 @@ func convertToActionGroup(actions []ActionRequest, triggerType string, waypointI
 	}
 
 	return &ActionGroup{
-		ActionGroupID:         waypointIndex,
+		ActionGroupID:         actionGroupID,
 		ActionGroupStartIndex: waypointIndex,
-		ActionGroupEndIndex:   waypointIndex,
+		ActionGroupEndIndex:   endIndex,
 		ActionGroupMode:       ActionGroupModeSequence,
 		ActionTrigger:         trigger,
 		Actions:               actionList,
@@ -427,7 +463,13 @@ func convertActionParams(actionReq ActionRequest) *ActionActuatorFuncParam {
 			posIndex := int(gimbalRotateAction.PayloadPositionIndex)
 			param.PayloadPositionIndex = &posIndex
 			param.GimbalHeadingYawBase = &gimbalRotateAction.GimbalHeadingYawBase
-			param.GimbalRotateMode = &gimbalRotateAction.GimbalRotateMode
+			// Validation only allows an empty mode when no angle is set, where
+			// the mode has no effect but the element is still required.
+			rotateMode := gimbalRotateAction.GimbalRotateMode
+			if rotateMode == "" {
+				rotateMode = GimbalRotateModeAbsoluteAngle
+			}
+			param.GimbalRotateMode = &rotateMode
 
 			pitchEnable := 0
 			if gimbalRotateAction.GimbalPitchRotateEnable {
@@ -606,6 +648,39 @@ func convertActionParams(actionReq ActionRequest) *ActionActuatorFuncParam {
 	return param
 }
 
+// convertToPayloadParams emits a payload parameter block per payload
+// position. A single payload only gets one when it is a LiDAR, which needs its
+// point-cloud settings; multi-payload missions list every position.
+func convertToPayloadParams(waylines *Waylines) []PayloadParam {
+	var params []PayloadParam
+	for i, payload := range waylines.payloads() {
+		capabilities, _ := CapabilitiesForPayload(payload.Model)
+		if capabilities.LiDAR == nil && len(waylines.AdditionalPayloads) == 0 {
+			continue
+		}
+
+		param := PayloadParam{
+			PayloadPositionIndex: int(payload.Position),
+			ImageFormat:          ImageFormatVisible,
+		}
+		// LiDAR settings describe the primary payload.
+		if settings := waylines.LiDAR; settings != nil && i == 0 {
+			recordPointCloud := 0
+			if settings.RecordPointCloud {
+				recordPointCloud = 1
+			}
+			param.IsRecordPointCloud = &recordPointCloud
+			param.ReturnMode = stringPtr(settings.ReturnMode)
+			param.ScanningMode = stringPtr(settings.ScanningMode)
+			if settings.SamplingRate != 0 {
+				param.SamplingRate = intPtr(settings.SamplingRate)
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
 func convertGlobalHeadingParam(waylines *Waylines) *GlobalWaypointHeadingParam {
 	headingMode := HeadingModeFollowWayline
 	if waylines.AircraftYawMode != "" {
@@ -618,29 +693,118 @@ func convertGlobalHeadingParam(waylines *Waylines) *GlobalWaypointHeadingParam {
 			headingMode = HeadingModeManually
 		case "free":
 			headingMode = HeadingModeFree
+		case HeadingModeTowardPOI:
+			headingMode = HeadingModeTowardPOI
 		}
 	}
 
-	return &GlobalWaypointHeadingParam{
-		WaypointHeadingMode: headingMode,
+	param := &GlobalWaypointHeadingParam{
+		WaypointHeadingMode:     headingMode,
+		WaypointHeadingPathMode: waylines.headingPathMode(),
+	}
+	if headingMode == HeadingModeTowardPOI {
+		param.WaypointPoiPoint = waylines.headingPOIPoint()
+	}
+	return param
+}
+
+// convertWaypointHeadingParam copies the mission heading mode, angle and POI
+// from the global heading param, so a waypoint that only overrides the path
+// mode keeps flying the mission heading.
+func convertWaypointHeadingParam(waylines *Waylines, waypoint WaylinesWaypoint) *WaypointHeadingParam {
+	global := convertGlobalHeadingParam(waylines)
+	param := &WaypointHeadingParam{
+		WaypointHeadingMode:        global.WaypointHeadingMode,
+		WaypointHeadingAngle:       float64Ptr(0),
+		WaypointPoiPoint:           stringPtr("0.000000,0.000000,0.000000"),
+		WaypointHeadingAngleEnable: intPtr(0),
+		WaypointHeadingPathMode:    waylines.waypointHeadingPathMode(waypoint),
+		WaypointHeadingPoiIndex:    intPtr(0),
+	}
+	if global.WaypointHeadingAngle != nil {
+		param.WaypointHeadingAngle = global.WaypointHeadingAngle
 	}
+	if global.WaypointPoiPoint != nil {
+		param.WaypointPoiPoint = global.WaypointPoiPoint
+	}
+	return param
 }
 
-func calculateWaylineStats(waypoints []WaylinesWaypoint, speed float64) (distance, duration float64) {
-	if len(waypoints) < 2 {
-		return 0, 0
-	}
-	totalDistance := 0.0
-	for i := 1; i < len(waypoints); i++ {
-		prev := waypoints[i-1]
-		curr := waypoints[i]
-		latDiff := curr.Latitude - prev.Latitude
-		lonDiff := curr.Longitude - prev.Longitude
-		segmentDistance := math.Sqrt(latDiff*latDiff+lonDiff*lonDiff) * 111000
-		totalDistance += segmentDistance
-	}
-	duration = totalDistance / speed
-	return totalDistance, duration
+// headingPathMode returns the mission heading path mode, defaulting to
+// followBadArc, which rotates the aircraft the shorter way between headings.
+func (w *Waylines) headingPathMode() string {
+	if w.HeadingPathMode == "" {
+		return HeadingPathModeFollowBadArc
+	}
+	return w.HeadingPathMode
+}
+
+// waypointHeadingPathMode returns the heading path mode of waypoint, falling
+// back to the mission setting.
+func (w *Waylines) waypointHeadingPathMode(waypoint WaylinesWaypoint) string {
+	if waypoint.HeadingPathMode != "" {
+		return waypoint.HeadingPathMode
+	}
+	return w.headingPathMode()
+}
+
+// headingPOIPoint formats HeadingPOI as a wpml:waypointPoiPoint. The height is
+// not used by the aircraft and is written as zero.
+func (w *Waylines) headingPOIPoint() *string {
+	if w.HeadingPOI == nil {
+		return nil
+	}
+	return stringPtr(fmt.Sprintf("%.6f,%.6f,0.000000", w.HeadingPOI.Latitude, w.HeadingPOI.Longitude))
+}
+
+// StopAndGoStabilizationHover is the hover, in seconds, inserted before each
+// capture action in stop-and-go missions so the aircraft settles first.
+const StopAndGoStabilizationHover = 1.0
+
+// DefaultTransitionalSpeed is the transitional speed, in m/s, used when
+// GlobalTransitionalSpeed is not set.
+const DefaultTransitionalSpeed = 6.0
+
+func (w *Waylines) transitionalSpeed() float64 {
+	if w.GlobalTransitionalSpeed == 0 {
+		return DefaultTransitionalSpeed
+	}
+	return w.GlobalTransitionalSpeed
+}
+
+func (w *Waylines) waypointWorkType() int {
+	if w.WorkType == WorkTypeStopAndGo {
+		return WaypointWorkTypeStopAndGo
+	}
+	return WaypointWorkTypeContinuous
+}
+
+func (w *Waylines) effectiveActions(waypoint WaylinesWaypoint) []ActionRequest {
+	if w.WorkType != WorkTypeStopAndGo {
+		return waypoint.Actions
+	}
+
+	actions := make([]ActionRequest, 0, len(waypoint.Actions))
+	for i, actionReq := range waypoint.Actions {
+		precededByHover := i > 0 && waypoint.Actions[i-1].Type == ActionTypeHover
+		if isCaptureAction(actionReq.Type) && !precededByHover {
+			actions = append(actions, ActionRequest{
+				Type:   ActionTypeHover,
+				Action: &HoverAction{HoverTime: StopAndGoStabilizationHover},
+			})
+		}
+		actions = append(actions, actionReq)
+	}
+	return actions
+}
+
+func isCaptureAction(actionType string) bool {
+	switch actionType {
+	case ActionTypeTakePhoto, ActionTypeAccurateShoot, ActionTypeOrientedShoot, ActionTypePanoShot:
+		return true
+	default:
+		return false
+	}
 }
 
 func intPtr(v int) *int {
@@ -663,29 +827,42 @@ func formatCoordinates(lon, lat float64) string {
 	return fmt.Sprintf("%g,%g", lon, lat)
 }
 
-func createWaypointTurnParam(waypoint WaylinesWaypoint, waylines *Waylines) *WaypointTurnParam {
-
-	turnMode := TurnModeToPointAndStopWithContinuityCurvature
-
-	if waypoint.WaypointTurnMode != "" {
+func createWaypointTurnParam(waypoint WaylinesWaypoint, index int, waylines *Waylines, version WPMLVersion) (*WaypointTurnParam, error) {
 
-		turnMode = waypoint.WaypointTurnMode
-	} else if waylines.GlobalWaypointTurnMode != "" {
+	turnMode := waylines.turnModeAt(index)
 
-		turnMode = waylines.GlobalWaypointTurnMode
+	if version == WPMLVersionLegacy {
+		radius, err := waylines.turnDampingRadius(index)
+		if err != nil {
+			return nil, err
+		}
+		return &WaypointTurnParam{
+			WaypointTurnMode:          turnMode,
+			WaypointTurnDampingRadius: &radius,
+		}, nil
 	}
 
-	dampingDist := 0.0
-	if waypoint.TurnDampingDist > 0 {
-		dampingDist = waypoint.TurnDampingDist
-	} else if waylines.GlobalTurnDampingDist > 0 {
-		dampingDist = waylines.GlobalTurnDampingDist
+	dampingDist, err := waylines.turnDampingDist(index)
+	if err != nil {
+		return nil, err
 	}
 
 	return &WaypointTurnParam{
 		WaypointTurnMode:        turnMode,
 		WaypointTurnDampingDist: &dampingDist,
+	}, nil
+}
+
+// templateTurnParam returns the default turn param of template placemarks in
+// the damping form of version.
+func templateTurnParam(version WPMLVersion) *WaypointTurnParam {
+	param := &WaypointTurnParam{WaypointTurnMode: TurnModeToPointAndStopWithContinuityCurvature}
+	if version == WPMLVersionLegacy {
+		param.WaypointTurnDampingRadius = float64Ptr(minTurnDampingDist)
+	} else {
+		param.WaypointTurnDampingDist = float64Ptr(minTurnDampingDist)
 	}
+	return param
 }
 
 func getUseStraightLine(waypoint WaylinesWaypoint, waylines *Waylines, turnMode string) *int {
@@ -1,49 +1,131 @@
 package wpml
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
 type Waylines struct {
-	Name                     string             `json:"name" validate:"required,min=1,max=100"`
-	Description              string             `json:"description,omitempty" validate:"max=500"`
-	DroneModel               DroneModel         `json:"drone_model" validate:"required,drone_model"`
-	PayloadModel             PayloadModel       `json:"payload_model" validate:"required,payload_model"`
-	PayloadPositionIndex     PayloadPosition    `json:"payload_position_index,omitempty" validate:"payload_position"`
-	TemplateType             TemplateType       `json:"template_type" validate:"required"`
-	GlobalHeight             float64            `json:"global_height,omitempty" validate:"min=5,max=1500"`
-	GlobalSpeed              float64            `json:"global_speed,omitempty" validate:"min=1,max=15"`
-	PhotoSettings            []string           `json:"photo_settings,omitempty" validate:"dive,oneof=wide zoom ir vision"`
-	UseLowLightSmart         bool               `json:"use_low_light_smart,omitempty"`
-	FinishAction             FinishAction       `json:"finish_action,omitempty"`
-	HeightType               HeightMode         `json:"height_type,omitempty" default:"relativeToStartPoint"`
-	ClimbMode                string             `json:"climb_mode,omitempty" validate:"oneof=vertical inclined"`
-	SafeHeight               float64            `json:"safe_height,omitempty" validate:"min=20,max=200"`
-	GlobalRTHHeight          float64            `json:"global_rth_height,omitempty" validate:"min=20,max=1500"`
-	AircraftYawMode          string             `json:"aircraft_yaw_mode,omitempty" validate:"oneof=followWayline followRoute manual free"`
-	GimbalPitchMode          string             `json:"gimbal_pitch_mode,omitempty" validate:"oneof=usePointSetting manual free"`
-	GlobalTransitionalSpeed  float64            `json:"global_transitional_speed,omitempty" validate:"min=1,max=15"`
-	TakeOffRefPointLatitude  float64            `json:"take_off_ref_point_latitude,omitempty" validate:"min=-90,max=90"`
-	TakeOffRefPointLongitude float64            `json:"take_off_ref_point_longitude,omitempty" validate:"min=-180,max=180"`
-	TakeOffRefPointHeight    float64            `json:"take_off_ref_point_height,omitempty"`
-	TakeOffRefPointAGLHeight *float64           `json:"take_off_ref_point_agl_height,omitempty"`
-	GlobalWaypointTurnMode   string             `json:"global_waypoint_turn_mode,omitempty" validate:"omitempty,oneof=coordinateTurn toPointAndStopWithDiscontinuityCurvature toPointAndStopWithContinuityCurvature toPointAndPassWithContinuityCurvature"`
-	GlobalUseStraightLine    *bool              `json:"global_use_straight_line,omitempty"`
-	GlobalTurnDampingDist    float64            `json:"global_turn_damping_dist,omitempty" validate:"min=0"`
-	Waypoints                []WaylinesWaypoint `json:"waypoints" validate:"required,min=1,dive"`
+	Name                     string              `json:"name" validate:"required,min=1,max=100"`
+	Description              string              `json:"description,omitempty" validate:"max=500"`
+	DroneModel               DroneModel          `json:"drone_model" validate:"required,drone_model"`
+	PayloadModel             PayloadModel        `json:"payload_model" validate:"required,payload_model"`
+	PayloadPositionIndex     PayloadPosition     `json:"payload_position_index,omitempty" validate:"payload_position"`
+	AdditionalPayloads       []PayloadConfig     `json:"additional_payloads,omitempty" validate:"dive"`
+	TemplateType             TemplateType        `json:"template_type" validate:"required"`
+	GlobalHeight             float64             `json:"global_height,omitempty" validate:"min=5,max=1500"`
+	GlobalSpeed              float64             `json:"global_speed,omitempty" validate:"min=1,max=15"`
+	PhotoSettings            []string            `json:"photo_settings,omitempty" validate:"dive,oneof=wide zoom ir vision"`
+	UseLowLightSmart         bool                `json:"use_low_light_smart,omitempty"`
+	FinishAction             FinishAction        `json:"finish_action,omitempty"`
+	ExitOnRCLost             RCLostAction        `json:"exit_on_rc_lost,omitempty" validate:"omitempty,oneof=goContinue executeLostAction"`
+	ExecuteRCLostAction      ExecuteRCLostAction `json:"execute_rc_lost_action,omitempty" validate:"omitempty,oneof=goBack landing hover"`
+	HeightType               HeightMode          `json:"height_type,omitempty" default:"relativeToStartPoint"`
+	ClimbMode                string              `json:"climb_mode,omitempty" validate:"oneof=vertical inclined"`
+	SafeHeight               float64             `json:"safe_height,omitempty" validate:"min=20,max=200"`
+	GlobalRTHHeight          float64             `json:"global_rth_height,omitempty" validate:"min=20,max=1500"`
+	AircraftYawMode          string              `json:"aircraft_yaw_mode,omitempty" validate:"oneof=followWayline followRoute manual free towardPOI"`
+	HeadingPOI               *LatLng             `json:"heading_poi,omitempty"`
+	HeadingPathMode          string              `json:"heading_path_mode,omitempty" validate:"omitempty,oneof=clockwise counterClockwise followBadArc"`
+	GimbalPitchMode          string              `json:"gimbal_pitch_mode,omitempty" validate:"oneof=usePointSetting manual free"`
+	GlobalTransitionalSpeed  float64             `json:"global_transitional_speed,omitempty" validate:"min=1,max=15"`
+	TakeOffRefPointLatitude  float64             `json:"take_off_ref_point_latitude,omitempty" validate:"min=-90,max=90"`
+	TakeOffRefPointLongitude float64             `json:"take_off_ref_point_longitude,omitempty" validate:"min=-180,max=180"`
+	TakeOffRefPointHeight    float64             `json:"take_off_ref_point_height,omitempty"`
+	TakeOffRefPointAGLHeight *float64            `json:"take_off_ref_point_agl_height,omitempty"`
+	GlobalWaypointTurnMode   string              `json:"global_waypoint_turn_mode,omitempty" validate:"omitempty,oneof=coordinateTurn toPointAndStopWithDiscontinuityCurvature toPointAndStopWithContinuityCurvature toPointAndPassWithContinuityCurvature"`
+	GlobalUseStraightLine    *bool               `json:"global_use_straight_line,omitempty"`
+	GlobalTurnDampingDist    float64             `json:"global_turn_damping_dist,omitempty" validate:"min=0"`
+	WorkType                 WorkType            `json:"work_type,omitempty" validate:"omitempty,oneof=continuous stopAndGo"`
+	WaypointEndType          WaypointEndType     `json:"waypoint_end_type,omitempty" validate:"omitempty,oneof=stop pass"`
+	LiDAR                    *LiDARSettings      `json:"lidar,omitempty"`
+	Mapping                  *MappingConfig      `json:"mapping,omitempty"`
+	Metadata                 map[string]string   `json:"metadata,omitempty"`
+	Waypoints                []WaylinesWaypoint  `json:"waypoints" validate:"required,min=1,dive"`
 }
 
 type WaylinesWaypoint struct {
-	Latitude         float64         `json:"latitude" validate:"required,min=-90,max=90"`
-	Longitude        float64         `json:"longitude" validate:"required,min=-180,max=180"`
-	Height           float64         `json:"height" validate:"required,min=5,max=500"`
-	Speed            float64         `json:"speed,omitempty" validate:"min=1,max=15"`
-	TriggerType      string          `json:"trigger_type,omitempty" validate:"oneof=reachPoint passPoint manual betweenAdjacentPoints multipleTiming multipleDistance"`
-	TriggerParam     float64         `json:"trigger_param,omitempty" validate:"min=0"`
-	WaypointTurnMode string          `json:"waypoint_turn_mode,omitempty" validate:"omitempty,oneof=coordinateTurn toPointAndStopWithDiscontinuityCurvature toPointAndStopWithContinuityCurvature toPointAndPassWithContinuityCurvature"`
-	UseStraightLine  *bool           `json:"use_straight_line,omitempty"`
-	TurnDampingDist  float64         `json:"turn_damping_dist,omitempty" validate:"min=0"`
-	Actions          []ActionRequest `json:"actions,omitempty" validate:"dive"`
+	Latitude          float64          `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude         float64          `json:"longitude" validate:"required,min=-180,max=180"`
+	Height            float64          `json:"height"`
+	HeightMode        HeightMode       `json:"height_mode,omitempty" validate:"omitempty,oneof=EGM96 relativeToStartPoint aboveGroundLevel realTimeFollowSurface"`
+	Speed             float64          `json:"speed,omitempty" validate:"omitempty,min=1,max=15"`
+	TriggerType       string           `json:"trigger_type,omitempty" validate:"oneof=reachPoint passPoint manual betweenAdjacentPoints multipleTiming multipleDistance"`
+	TriggerParam      float64          `json:"trigger_param,omitempty" validate:"min=0"`
+	ActionTrigger     *ActionTrigger   `json:"action_trigger,omitempty"`
+	HeadingPathMode   string           `json:"heading_path_mode,omitempty" validate:"omitempty,oneof=clockwise counterClockwise followBadArc"`
+	WaypointTurnMode  string           `json:"waypoint_turn_mode,omitempty" validate:"omitempty,oneof=coordinateTurn toPointAndStopWithDiscontinuityCurvature toPointAndStopWithContinuityCurvature toPointAndPassWithContinuityCurvature"`
+	UseStraightLine   *bool            `json:"use_straight_line,omitempty"`
+	TurnDampingDist   float64          `json:"turn_damping_dist,omitempty" validate:"min=0"`
+	TurnDampingRadius float64          `json:"turn_damping_radius,omitempty" validate:"min=0"`
+	Actions           []ActionRequest  `json:"actions,omitempty" validate:"dive"`
+	IntervalCapture   *IntervalCapture `json:"interval_capture,omitempty"`
+}
+
+// LiDARSettings configures point-cloud recording for LiDAR payloads. It is
+// emitted as the template payload parameters and rejected for other payloads.
+type LiDARSettings struct {
+	RecordPointCloud bool   `json:"record_point_cloud"`
+	ReturnMode       string `json:"return_mode,omitempty" validate:"omitempty,oneof=singleReturnStrongest dualReturn tripleReturn"`
+	ScanningMode     string `json:"scanning_mode,omitempty" validate:"omitempty,oneof=repetitive nonRepetitive"`
+	SamplingRate     int    `json:"sampling_rate,omitempty" validate:"min=0"`
+}
+
+// MappingConfig holds the photogrammetry settings of mapping templates.
+// Overlaps are percentages of the image footprint shared with the next photo
+// along the flight line (front) and with the neighboring line (side).
+type MappingConfig struct {
+	FrontOverlap int `json:"front_overlap"`
+	SideOverlap  int `json:"side_overlap"`
 }
 
 func (w *Waylines) Validate() error {
-	return NewWPMLValidator().ValidateStruct(w)
+	if err := NewWPMLValidator().ValidateStruct(w); err != nil {
+		return w.explainStructError(err)
+	}
+
+	return w.validateMissionRules(false)
+}
+
+// ValidateDraft validates a mission that is still being edited. It applies the
+// same rules as Validate with two relaxations: Waypoints may be empty (the
+// required,min=1 rule is skipped), and waypoint actions are neither validated
+// nor cross-checked, so incomplete action requests do not block feedback on the
+// rest of the mission. Waypoints that are present must still satisfy their own
+// field rules and the mission rules that do not inspect actions.
+func (w *Waylines) ValidateDraft() error {
+	validator := NewWPMLValidator()
+	if err := validator.ValidateStructExcept(w, "Waypoints"); err != nil {
+		return w.explainStructError(err)
+	}
+
+	for i := range w.Waypoints {
+		if err := validator.ValidateStructExcept(&w.Waypoints[i], "Actions"); err != nil {
+			return fmt.Errorf(ErrDraftWaypointValidationFailed, i, err)
+		}
+	}
+
+	return w.validateMissionRules(true)
+}
+
+// explainStructError replaces a failed height_range struct check, which only
+// names the field, with the waypoint height rule's error, which names the
+// range of the height mode.
+func (w *Waylines) explainStructError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	for _, fieldError := range validationErrors {
+		if fieldError.Tag() == heightRangeTag {
+			if heightErr := validateWaypointHeights(w); heightErr != nil {
+				return heightErr
+			}
+		}
+	}
+	return err
 }
 
 func (w *Waylines) ApplyDefaults() {
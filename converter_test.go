@@ -84,6 +84,7 @@ func TestConvertWaylinesToWPMLMission(t *testing.T) {
 				TemplateType:            TemplateTypeWaypoint,
 				GlobalHeight:            80.0,
 				GlobalSpeed:             8.0,
+				PhotoSettings:           []string{"wide"},
 				ClimbMode:               "vertical",
 				SafeHeight:              30.0,
 				GlobalRTHHeight:         100.0,
@@ -145,6 +146,7 @@ func TestConvertWaylinesToWPMLMissionWithJSON(t *testing.T) {
 		"template_type": "waypoint",
 		"global_height": 100,
 		"global_speed": 10,
+		"photo_settings": ["wide"],
 		"climb_mode": "vertical",
 		"safe_height": 30,
 		"global_rth_height": 120,
@@ -314,3 +316,237 @@ func TestWaylinesApplyDefaults(t *testing.T) {
 	// After applying defaults
 	assert.Equal(t, HeightModeRelativeToStartPoint, waylines.HeightType)
 }
+
+func TestWaylinesValidateDraft(t *testing.T) {
+	t.Run("Draft without waypoints is valid", func(t *testing.T) {
+		waylines := createValidWaylines("Draft Mission")
+		waylines.Waypoints = nil
+
+		assert.Error(t, waylines.Validate())
+		assert.NoError(t, waylines.ValidateDraft())
+	})
+
+	t.Run("Draft still validates global settings", func(t *testing.T) {
+		waylines := createValidWaylines("Draft Mission")
+		waylines.Waypoints = nil
+		waylines.GlobalSpeed = 50.0
+
+		assert.Error(t, waylines.ValidateDraft())
+	})
+
+	t.Run("Draft still validates present waypoints", func(t *testing.T) {
+		waylines := createValidWaylines("Draft Mission")
+		waylines.Waypoints[0].Latitude = -100.0
+
+		err := waylines.ValidateDraft()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoint 0")
+	})
+
+	t.Run("Draft skips action validation", func(t *testing.T) {
+		waylines := createValidWaylines("Draft Mission")
+		waylines.Waypoints[0].Actions = []ActionRequest{
+			{Type: ActionTypeHover, Action: &HoverAction{}},
+		}
+
+		assert.Error(t, waylines.Validate())
+		assert.NoError(t, waylines.ValidateDraft())
+	})
+}
+
+func TestConvert_StopAndGoWorkType(t *testing.T) {
+	waylines := createValidWaylines("Stop And Go")
+	waylines.WorkType = WorkTypeStopAndGo
+	waylines.Waypoints[0].Actions = []ActionRequest{
+		{Type: ActionTypeTakePhoto, Action: &TakePhotoAction{PayloadPositionIndex: PayloadPosition0}},
+	}
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	placemark := mission.Waylines.Document.Folders[0].Placemarks[0]
+	assert.Equal(t, TurnModeToPointAndStopWithDiscontinuityCurvature, placemark.WaypointTurnParam.WaypointTurnMode)
+	assert.Equal(t, WaypointWorkTypeStopAndGo, *placemark.WaypointWorkType)
+
+	require.Len(t, placemark.ActionGroups, 1)
+	actions := placemark.ActionGroups[0].Actions
+	require.Len(t, actions, 2)
+	assert.Equal(t, ActionTypeHover, actions[0].ActionActuatorFunc)
+	assert.Equal(t, StopAndGoStabilizationHover, *actions[0].ActionActuatorFuncParam.HoverTime)
+	assert.Equal(t, ActionTypeTakePhoto, actions[1].ActionActuatorFunc)
+	assert.Equal(t, 1, actions[1].ActionID)
+
+	assert.Len(t, waylines.Waypoints[0].Actions, 1, "input waypoint actions are not modified")
+}
+
+func TestConvert_StopAndGoKeepsExistingHover(t *testing.T) {
+	waylines := createValidWaylines("Stop And Go")
+	waylines.WorkType = WorkTypeStopAndGo
+	waylines.Waypoints[0].Actions = []ActionRequest{
+		{Type: ActionTypeHover, Action: &HoverAction{HoverTime: 3}},
+		{Type: ActionTypeTakePhoto, Action: &TakePhotoAction{PayloadPositionIndex: PayloadPosition0}},
+	}
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	actions := mission.Waylines.Document.Folders[0].Placemarks[0].ActionGroups[0].Actions
+	assert.Len(t, actions, 2)
+}
+
+func TestConvert_ContinuousWorkType(t *testing.T) {
+	waylines := createValidWaylines("Continuous")
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	placemark := mission.Waylines.Document.Folders[0].Placemarks[0]
+	assert.Equal(t, WaypointWorkTypeContinuous, *placemark.WaypointWorkType)
+	assert.Equal(t, TurnModeToPointAndStopWithContinuityCurvature, placemark.WaypointTurnParam.WaypointTurnMode)
+}
+
+func TestConvert_LiDARPayloadParam(t *testing.T) {
+	waylines := createValidWaylines("LiDAR Mapping")
+	waylines.DroneModel = DroneM300RTK
+	waylines.PayloadModel = PayloadL1
+	waylines.LiDAR = &LiDARSettings{
+		RecordPointCloud: true,
+		ReturnMode:       LiDARReturnModeDual,
+		ScanningMode:     LiDARScanningModeNonRepetitive,
+		SamplingRate:     240000,
+	}
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	params := mission.Template.Document.Folders[0].PayloadParams
+	require.Len(t, params, 1)
+	param := params[0]
+	assert.Equal(t, 1, *param.IsRecordPointCloud)
+	assert.Equal(t, LiDARReturnModeDual, *param.ReturnMode)
+	assert.Equal(t, LiDARScanningModeNonRepetitive, *param.ScanningMode)
+	assert.Equal(t, 240000, *param.SamplingRate)
+
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, params, parsed.Template.Document.Folders[0].PayloadParams)
+}
+
+func TestConvert_NonLiDARPayloadHasNoPayloadParam(t *testing.T) {
+	mission, err := ConvertWaylinesToWPMLMission(createValidWaylines("Camera"))
+	require.NoError(t, err)
+
+	assert.Empty(t, mission.Template.Document.Folders[0].PayloadParams)
+}
+
+func TestConvert_UseGlobalSpeed(t *testing.T) {
+	waylines := waylinesAt("Global Speed", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	waylines.GlobalSpeed = 10
+	waylines.Waypoints[0].Speed = 0
+	waylines.Waypoints[1].Speed = 4
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+
+	inherited := parsed.Template.Document.Folders[0].Placemarks[0]
+	require.NotNil(t, inherited.UseGlobalSpeed)
+	assert.Equal(t, 1, *inherited.UseGlobalSpeed)
+	assert.Nil(t, inherited.WaypointSpeed)
+
+	override := parsed.Template.Document.Folders[0].Placemarks[1]
+	require.NotNil(t, override.UseGlobalSpeed)
+	assert.Equal(t, 0, *override.UseGlobalSpeed)
+	require.NotNil(t, override.WaypointSpeed)
+	assert.Equal(t, 4.0, *override.WaypointSpeed)
+
+	// The executable wayline always carries the speed each waypoint flies at.
+	executed := parsed.Waylines.Document.Folders[0].Placemarks
+	assert.Equal(t, 10.0, *executed[0].WaypointSpeed)
+	assert.Equal(t, 4.0, *executed[1].WaypointSpeed)
+}
+
+func TestConvert_HeadingPathMode(t *testing.T) {
+	waylines := waylinesAt("Heading Path", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	folder := mission.Template.Document.Folders[0]
+	assert.Equal(t, HeadingPathModeFollowBadArc, folder.GlobalWaypointHeadingParam.WaypointHeadingPathMode, "defaults to the shorter rotation")
+
+	waylines.HeadingPathMode = HeadingPathModeClockwise
+	waylines.Waypoints[1].HeadingPathMode = HeadingPathModeCounterClockwise
+	require.NoError(t, waylines.Validate())
+
+	mission, err = ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	folder = mission.Template.Document.Folders[0]
+	assert.Equal(t, HeadingPathModeClockwise, folder.GlobalWaypointHeadingParam.WaypointHeadingPathMode)
+	assert.Equal(t, 1, *folder.Placemarks[0].UseGlobalHeadingParam)
+	assert.Equal(t, 0, *folder.Placemarks[1].UseGlobalHeadingParam)
+	assert.Equal(t, HeadingPathModeCounterClockwise, folder.Placemarks[1].WaypointHeadingParam.WaypointHeadingPathMode)
+
+	executed := mission.Waylines.Document.Folders[0].Placemarks
+	assert.Equal(t, HeadingPathModeClockwise, executed[0].WaypointHeadingParam.WaypointHeadingPathMode)
+	assert.Equal(t, HeadingPathModeCounterClockwise, executed[1].WaypointHeadingParam.WaypointHeadingPathMode)
+
+	waylines.HeadingPathMode = "shortest"
+	assert.Error(t, waylines.Validate())
+	waylines.HeadingPathMode = ""
+	waylines.Waypoints[0].HeadingPathMode = "shortest"
+	assert.Error(t, waylines.Validate())
+}
+
+func TestConvert_HeadingPathModeKeepsMissionHeading(t *testing.T) {
+	waylines := waylinesAt("Manual Heading", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	waylines.AircraftYawMode = "manual"
+	waylines.Waypoints[0].HeadingPathMode = HeadingPathModeClockwise
+	require.NoError(t, waylines.Validate())
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	folder := mission.Template.Document.Folders[0]
+	assert.Equal(t, HeadingModeManually, folder.GlobalWaypointHeadingParam.WaypointHeadingMode)
+	assert.Equal(t, 0, *folder.Placemarks[0].UseGlobalHeadingParam)
+	heading := folder.Placemarks[0].WaypointHeadingParam
+	assert.Equal(t, HeadingModeManually, heading.WaypointHeadingMode, "overriding the path mode keeps the mission heading mode")
+	assert.Equal(t, HeadingPathModeClockwise, heading.WaypointHeadingPathMode)
+
+	waylines.AircraftYawMode = HeadingModeTowardPOI
+	waylines.HeadingPOI = &LatLng{Latitude: 39.905, Longitude: 116.405}
+	mission, err = ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	heading = mission.Template.Document.Folders[0].Placemarks[0].WaypointHeadingParam
+	assert.Equal(t, HeadingModeTowardPOI, heading.WaypointHeadingMode)
+	assert.Equal(t, "39.905000,116.405000,0.000000", *heading.WaypointPoiPoint)
+}
+
+func TestConvert_WaypointEndType(t *testing.T) {
+	waylines := waylinesAt("End Type", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400}, [2]float64{39.902, 116.400})
+	waylines.GlobalWaypointTurnMode = TurnModeCoordinateTurn
+	waylines.WaypointEndType = WaypointEndTypeStop
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	executed := mission.Waylines.Document.Folders[0].Placemarks
+	assert.Equal(t, TurnModeCoordinateTurn, executed[1].WaypointTurnParam.WaypointTurnMode)
+	assert.Equal(t, TurnModeToPointAndStopWithDiscontinuityCurvature, executed[2].WaypointTurnParam.WaypointTurnMode)
+
+	template := mission.Template.Document.Folders[0].Placemarks
+	assert.Equal(t, 1, *template[1].UseGlobalTurnParam)
+	assert.Equal(t, 0, *template[2].UseGlobalTurnParam)
+	assert.Equal(t, TurnModeToPointAndStopWithDiscontinuityCurvature, template[2].WaypointTurnParam.WaypointTurnMode)
+
+	waylines.GlobalWaypointTurnMode = ""
+	waylines.WaypointEndType = WaypointEndTypePass
+	mission, err = ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	executed = mission.Waylines.Document.Folders[0].Placemarks
+	assert.Equal(t, TurnModeToPointAndStopWithContinuityCurvature, executed[1].WaypointTurnParam.WaypointTurnMode)
+	assert.Equal(t, TurnModeToPointAndPassWithContinuityCurvature, executed[2].WaypointTurnParam.WaypointTurnMode)
+}
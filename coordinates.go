@@ -0,0 +1,41 @@
+package wpml
+
+import "math"
+
+// NormalizeLongitude wraps lon into [-180, 180], so 185 becomes -175.
+// Longitudes already in range are returned unchanged, and non-finite values
+// are left for validation to reject.
+func NormalizeLongitude(lon float64) float64 {
+	if (lon >= -180 && lon <= 180) || math.IsInf(lon, 0) || math.IsNaN(lon) {
+		return lon
+	}
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
+
+// NormalizeCoordinates rewrites the waypoint, takeoff reference point and
+// heading POI coordinates in place: longitudes are wrapped with NormalizeLongitude and
+// latitudes clamped to [-90, 90]. It changes the mission's values, so call it
+// before Validate only when ingesting coordinates from an untrusted source,
+// where out-of-range values come from wrapping errors rather than wrong
+// positions.
+func (w *Waylines) NormalizeCoordinates() {
+	for i := range w.Waypoints {
+		wp := &w.Waypoints[i]
+		wp.Latitude = clampLatitude(wp.Latitude)
+		wp.Longitude = NormalizeLongitude(wp.Longitude)
+	}
+	w.TakeOffRefPointLatitude = clampLatitude(w.TakeOffRefPointLatitude)
+	w.TakeOffRefPointLongitude = NormalizeLongitude(w.TakeOffRefPointLongitude)
+	if poi := w.HeadingPOI; poi != nil {
+		poi.Latitude = clampLatitude(poi.Latitude)
+		poi.Longitude = NormalizeLongitude(poi.Longitude)
+	}
+}
+
+func clampLatitude(lat float64) float64 {
+	return math.Max(-90, math.Min(90, lat))
+}
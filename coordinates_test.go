@@ -0,0 +1,48 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLongitude(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected float64
+	}{
+		{input: 116.4, expected: 116.4},
+		{input: 180, expected: 180},
+		{input: -180, expected: -180},
+		{input: 185, expected: -175},
+		{input: -185, expected: 175},
+		{input: 540, expected: -180},
+		{input: 725, expected: 5},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, NormalizeLongitude(tt.input), 1e-9, "input %v", tt.input)
+	}
+}
+
+func TestNormalizeCoordinates(t *testing.T) {
+	waylines := createValidWaylines("Wrapped")
+	waylines.Waypoints[0].Longitude = 185
+	waylines.Waypoints[0].Latitude = 90.0000001
+	require.Error(t, waylines.Validate())
+
+	waylines.NormalizeCoordinates()
+	assert.InDelta(t, -175, waylines.Waypoints[0].Longitude, 1e-9)
+	assert.Equal(t, 90.0, waylines.Waypoints[0].Latitude)
+	assert.NoError(t, waylines.Validate())
+
+	waylines.AircraftYawMode = HeadingModeTowardPOI
+	waylines.HeadingPOI = &LatLng{Latitude: -90.5, Longitude: -190}
+	require.Error(t, waylines.Validate())
+
+	waylines.NormalizeCoordinates()
+	assert.Equal(t, -90.0, waylines.HeadingPOI.Latitude)
+	assert.InDelta(t, 170, waylines.HeadingPOI.Longitude, 1e-9)
+	assert.NoError(t, waylines.Validate())
+}
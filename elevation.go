@@ -0,0 +1,196 @@
+package wpml
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ElevationProvider looks up terrain elevation, in meters above the EGM96
+// geoid, for a coordinate. Implementations typically query a DEM service.
+type ElevationProvider interface {
+	Elevation(ctx context.Context, point LatLng) (float64, error)
+}
+
+// BatchElevationProvider is an ElevationProvider that can look up many points
+// in one request. CachingElevationProvider uses it for cache misses when the
+// wrapped provider implements it.
+type BatchElevationProvider interface {
+	ElevationProvider
+	// Elevations returns one elevation per point, in the order given.
+	Elevations(ctx context.Context, points []LatLng) ([]float64, error)
+}
+
+const (
+	// DefaultElevationGridResolution is the default cache cell size in
+	// degrees, about 11 m of latitude, which is finer than most DEMs.
+	DefaultElevationGridResolution = 0.0001
+	// DefaultElevationCacheSize is the default number of cached cells.
+	DefaultElevationCacheSize = 10000
+)
+
+// CachingElevationOptions configures a CachingElevationProvider. Zero values
+// select the defaults.
+type CachingElevationOptions struct {
+	// GridResolution is the cell size in degrees. Lookups are rounded to the
+	// nearest cell center, so all points in a cell share one elevation.
+	GridResolution float64
+	// CacheSize is the maximum number of cells kept; the least recently used
+	// cell is evicted first.
+	CacheSize int
+}
+
+type elevationCell struct {
+	lat, lng int64
+}
+
+type elevationEntry struct {
+	cell      elevationCell
+	elevation float64
+}
+
+// CachingElevationProvider memoizes another provider's lookups on a grid of
+// rounded coordinates. It is safe for concurrent use; errors are not cached.
+type CachingElevationProvider struct {
+	provider   ElevationProvider
+	resolution float64
+	size       int
+
+	mu      sync.Mutex
+	entries map[elevationCell]*list.Element
+	recency *list.List
+}
+
+func NewCachingElevationProvider(provider ElevationProvider, opts CachingElevationOptions) *CachingElevationProvider {
+	if opts.GridResolution <= 0 {
+		opts.GridResolution = DefaultElevationGridResolution
+	}
+	if opts.CacheSize <= 0 {
+		opts.CacheSize = DefaultElevationCacheSize
+	}
+	return &CachingElevationProvider{
+		provider:   provider,
+		resolution: opts.GridResolution,
+		size:       opts.CacheSize,
+		entries:    make(map[elevationCell]*list.Element),
+		recency:    list.New(),
+	}
+}
+
+func (c *CachingElevationProvider) Elevation(ctx context.Context, point LatLng) (float64, error) {
+	elevations, err := c.Elevations(ctx, []LatLng{point})
+	if err != nil {
+		return 0, err
+	}
+	return elevations[0], nil
+}
+
+// Elevations answers what it can from the cache and looks up the remaining
+// cells once each, in a single batch when the wrapped provider supports it.
+func (c *CachingElevationProvider) Elevations(ctx context.Context, points []LatLng) ([]float64, error) {
+	elevations := make([]float64, len(points))
+	cells := make([]elevationCell, len(points))
+	missing := make(map[elevationCell][]int)
+	var misses []elevationCell
+
+	c.mu.Lock()
+	for i, point := range points {
+		cells[i] = c.cellOf(point)
+		if elevation, ok := c.lookup(cells[i]); ok {
+			elevations[i] = elevation
+			continue
+		}
+		if _, seen := missing[cells[i]]; !seen {
+			misses = append(misses, cells[i])
+		}
+		missing[cells[i]] = append(missing[cells[i]], i)
+	}
+	c.mu.Unlock()
+
+	if len(misses) == 0 {
+		return elevations, nil
+	}
+
+	fetched, err := c.fetch(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for j, cell := range misses {
+		c.store(cell, fetched[j])
+		for _, i := range missing[cell] {
+			elevations[i] = fetched[j]
+		}
+	}
+	return elevations, nil
+}
+
+func (c *CachingElevationProvider) fetch(ctx context.Context, cells []elevationCell) ([]float64, error) {
+	centers := make([]LatLng, len(cells))
+	for i, cell := range cells {
+		centers[i] = c.centerOf(cell)
+	}
+
+	if batch, ok := c.provider.(BatchElevationProvider); ok {
+		elevations, err := batch.Elevations(ctx, centers)
+		if err != nil {
+			return nil, fmt.Errorf(ErrElevationLookupFailed, err)
+		}
+		if len(elevations) != len(centers) {
+			return nil, fmt.Errorf(ErrElevationCountMismatch, len(elevations), len(centers))
+		}
+		return elevations, nil
+	}
+
+	elevations := make([]float64, len(centers))
+	for i, center := range centers {
+		elevation, err := c.provider.Elevation(ctx, center)
+		if err != nil {
+			return nil, fmt.Errorf(ErrElevationLookupFailed, err)
+		}
+		elevations[i] = elevation
+	}
+	return elevations, nil
+}
+
+func (c *CachingElevationProvider) cellOf(point LatLng) elevationCell {
+	return elevationCell{
+		lat: int64(math.Round(point.Latitude / c.resolution)),
+		lng: int64(math.Round(point.Longitude / c.resolution)),
+	}
+}
+
+func (c *CachingElevationProvider) centerOf(cell elevationCell) LatLng {
+	return LatLng{
+		Latitude:  float64(cell.lat) * c.resolution,
+		Longitude: float64(cell.lng) * c.resolution,
+	}
+}
+
+// lookup and store must be called with c.mu held.
+func (c *CachingElevationProvider) lookup(cell elevationCell) (float64, bool) {
+	element, ok := c.entries[cell]
+	if !ok {
+		return 0, false
+	}
+	c.recency.MoveToFront(element)
+	return element.Value.(*elevationEntry).elevation, true
+}
+
+func (c *CachingElevationProvider) store(cell elevationCell, elevation float64) {
+	if element, ok := c.entries[cell]; ok {
+		element.Value.(*elevationEntry).elevation = elevation
+		c.recency.MoveToFront(element)
+		return
+	}
+	c.entries[cell] = c.recency.PushFront(&elevationEntry{cell: cell, elevation: elevation})
+	for c.recency.Len() > c.size {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*elevationEntry).cell)
+	}
+}
@@ -0,0 +1,110 @@
+package wpml
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockElevationProvider struct {
+	calls   []LatLng
+	batches [][]LatLng
+	err     error
+}
+
+func (m *mockElevationProvider) Elevation(ctx context.Context, point LatLng) (float64, error) {
+	m.calls = append(m.calls, point)
+	if m.err != nil {
+		return 0, m.err
+	}
+	return point.Latitude * 10, nil
+}
+
+type mockBatchElevationProvider struct {
+	mockElevationProvider
+}
+
+func (m *mockBatchElevationProvider) Elevations(ctx context.Context, points []LatLng) ([]float64, error) {
+	m.batches = append(m.batches, points)
+	elevations := make([]float64, len(points))
+	for i, point := range points {
+		elevations[i] = point.Latitude * 10
+	}
+	return elevations, nil
+}
+
+func TestCachingElevationProvider_CacheHits(t *testing.T) {
+	mock := &mockElevationProvider{}
+	provider := NewCachingElevationProvider(mock, CachingElevationOptions{GridResolution: 0.001})
+	ctx := context.Background()
+
+	first, err := provider.Elevation(ctx, LatLng{Latitude: 39.90012, Longitude: 116.40004})
+	require.NoError(t, err)
+	second, err := provider.Elevation(ctx, LatLng{Latitude: 39.90031, Longitude: 116.39981})
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second, "points in the same cell share an elevation")
+	require.Len(t, mock.calls, 1)
+	assert.InDelta(t, 39.900, mock.calls[0].Latitude, 1e-9, "lookups use the cell center")
+	assert.InDelta(t, 116.400, mock.calls[0].Longitude, 1e-9)
+
+	_, err = provider.Elevation(ctx, LatLng{Latitude: 39.902, Longitude: 116.4})
+	require.NoError(t, err)
+	assert.Len(t, mock.calls, 2)
+}
+
+func TestCachingElevationProvider_Batches(t *testing.T) {
+	mock := &mockBatchElevationProvider{}
+	provider := NewCachingElevationProvider(mock, CachingElevationOptions{GridResolution: 0.001})
+	ctx := context.Background()
+
+	_, err := provider.Elevation(ctx, LatLng{Latitude: 39.900, Longitude: 116.4})
+	require.NoError(t, err)
+
+	elevations, err := provider.Elevations(ctx, []LatLng{
+		{Latitude: 39.900, Longitude: 116.4},
+		{Latitude: 39.901, Longitude: 116.4},
+		{Latitude: 39.9011, Longitude: 116.4},
+		{Latitude: 39.902, Longitude: 116.4},
+	})
+	require.NoError(t, err)
+
+	assert.InDeltaSlice(t, []float64{399.00, 399.01, 399.01, 399.02}, elevations, 1e-9)
+	require.Len(t, mock.batches, 2)
+	assert.Len(t, mock.batches[1], 2, "cached and duplicate cells are not requested again")
+	assert.Empty(t, mock.calls, "batch providers are not queried point by point")
+}
+
+func TestCachingElevationProvider_Eviction(t *testing.T) {
+	mock := &mockElevationProvider{}
+	provider := NewCachingElevationProvider(mock, CachingElevationOptions{GridResolution: 0.001, CacheSize: 2})
+	ctx := context.Background()
+
+	a := LatLng{Latitude: 39.900, Longitude: 116.4}
+	b := LatLng{Latitude: 39.901, Longitude: 116.4}
+	c := LatLng{Latitude: 39.902, Longitude: 116.4}
+	for _, point := range []LatLng{a, b, a, c, a, b} {
+		_, err := provider.Elevation(ctx, point)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []LatLng{a, b, c, b}, mock.calls, "b is evicted as least recently used when c is added")
+}
+
+func TestCachingElevationProvider_ErrorsNotCached(t *testing.T) {
+	mock := &mockElevationProvider{err: errors.New("service unavailable")}
+	provider := NewCachingElevationProvider(mock, CachingElevationOptions{})
+	point := LatLng{Latitude: 39.9, Longitude: 116.4}
+
+	_, err := provider.Elevation(context.Background(), point)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service unavailable")
+
+	mock.err = nil
+	_, err = provider.Elevation(context.Background(), point)
+	require.NoError(t, err)
+	assert.Len(t, mock.calls, 2)
+}
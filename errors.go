@@ -4,10 +4,13 @@ import "errors"
 
 const (
 	ErrWaylinesValidationFailed = "waylines validation failed: %w"
+	ErrUnsupportedWPMLVersion   = "unsupported WPML target version %q"
+	ErrInvalidRenderOptions     = "invalid render options: %w"
 	ErrConvertMissionConfig     = "failed to convert mission config: %w"
 	ErrConvertTemplateFolder    = "failed to convert template folder: %w"
 	ErrConvertWaylineFolder     = "failed to convert wayline folder: %w"
 	ErrConvertWaypoint          = "failed to convert waypoint %d: %w"
+	ErrAllocateActionGroupIDs   = "failed to allocate action group IDs: %w"
 
 	ErrGenerateKMZBuffer   = "failed to generate KMZ buffer: %w"
 	ErrCreateDirectory     = "failed to create directory: %w"
@@ -26,6 +29,10 @@ const (
 	ErrReadWaylinesWPML    = "failed to read waylines.wpml: %w"
 	ErrParseTemplateKML    = "failed to parse template.kml: %w"
 	ErrParseWaylinesWPML   = "failed to parse waylines.wpml: %w"
+	ErrWriteTarHeader      = "failed to write tar header for %s: %w"
+	ErrWriteTarEntry       = "failed to write tar entry %s: %w"
+	ErrCloseTarWriter      = "failed to close tar writer: %w"
+	ErrCloseGzipWriter     = "failed to close gzip writer: %w"
 
 	ErrMarshalDocument           = "failed to marshal document: %w"
 	ErrUnmarshalDocument         = "failed to unmarshal document: %w"
@@ -36,6 +43,7 @@ const (
 	ErrInvalidXML                = "invalid XML: %w"
 	ErrMarshalTemplateDocument   = "failed to marshal template document: %w"
 	ErrMarshalWaylinesDocument   = "failed to marshal waylines document: %w"
+	ErrMarshalKML                = "failed to marshal KML preview: %w"
 	ErrUnmarshalTemplateDocument = "failed to unmarshal template document: %w"
 	ErrUnmarshalWaylinesDocument = "failed to unmarshal waylines document: %w"
 
@@ -51,6 +59,79 @@ const (
 	ErrFieldValidationFailed            = "field %s validation failed: %w"
 	ErrFieldRequiredForDroneModel       = "field %s is required for drone model %d"
 	ErrFieldRequiredForPayloadModel     = "field %s is required for payload model %d"
+
+	ErrElevationLookupFailed         = "elevation lookup failed: %w"
+	ErrElevationCountMismatch        = "elevation provider returned %d elevations for %d points"
+	ErrDraftWaypointValidationFailed = "waypoint %d validation failed: %w"
+	ErrWaypointHeightBelowReference  = "waypoint %d height %.1fm would put the aircraft at or below the %s, heights must be positive in %s height mode"
+	ErrUnknownHeightMode             = "waypoint %d uses unknown height mode %q"
+	ErrWaypointHeightOutOfRange      = "waypoint %d height %.1fm is outside the %.0f to %.0fm range allowed in %s height mode"
+	ErrWaypointHeightMode            = "waypoint %d: %w"
+	ErrHeightModeNotConvertible      = "%s heights cannot be converted to the mission height mode %s without terrain elevation; use the mission height mode"
+	ErrHeightModeNeedsTakeOffRef     = "converting a %s height to the mission height mode %s needs a takeoff reference point"
+	ErrConvertedHeightOutOfRange     = "waypoint %d height %.1fm in %s height mode is %.1fm in the mission height mode %s, outside the %.0f to %.0fm range"
+	ErrTakeoffClearanceTooLow        = "waypoint %d is %.1fm above the takeoff point, below the required clearance of %.1fm (height mode %s)"
+	ErrTakeOffRefHeightWithoutPoint  = "takeoff reference heights are set without a takeoff reference point latitude and longitude and would be ignored (height mode %s, TakeOffRefPointHeight %.1fm, TakeOffRefPointAGLHeight %s)"
+	ErrTakeOffRefAGLHeightInEGM96    = "takeoff reference AGL height %.1fm does not apply to height mode EGM96, which references the takeoff point by its absolute TakeOffRefPointHeight (%.1fm)"
+	ErrTakeOffRefMissingAGLHeight    = "height mode %s measures heights above the terrain, so the takeoff reference point needs TakeOffRefPointAGLHeight, but only the absolute TakeOffRefPointHeight %.1fm is set"
+	ErrStopAndGoGlobalTurnMode       = "global turn mode %s does not stop at waypoints and cannot be used with work type stopAndGo"
+	ErrStopAndGoWaypointTurnMode     = "waypoint %d: turn mode %s does not stop at the waypoint and cannot be used with work type stopAndGo"
+	ErrStopAndGoWaypointEndType      = "waypoint end type pass does not stop at the last waypoint and cannot be used with work type stopAndGo"
+	ErrWaypointEndTypeTurnMode       = "waypoint end type %s conflicts with turn mode %s of the last waypoint %d"
+	ErrLiDARSettingsUnsupported      = "LiDAR settings are only valid for LiDAR payloads, payload %d does not record point clouds"
+	ErrLiDARSettingUnsupported       = "payload %d does not support LiDAR %s %v, supported values are %v"
+	ErrTurnDampingDistAndRadius      = "waypoint %d sets both a turn damping distance and a turn damping radius, set only one"
+	ErrTurnDampingTooLarge           = "waypoint %d: turn damping distance %.1fm exceeds half of the shortest adjacent leg (%.1fm)"
+	ErrTurnDampingRadiusNearReversal = "waypoint %d: turn damping radius %.1fm cannot be converted to a distance at a %.1f° turn, turns sharper than %.0f° nearly reverse the route"
+	ErrTurnDampingDistWithoutTurn    = "waypoint %d: turn damping distance %.1fm cannot be converted to a radius because the aircraft does not turn there"
+	ErrIntervalCaptureNotStopped     = "waypoint %d starts a time-lapse capture that is never stopped, so it keeps capturing after the last waypoint and during return to home"
+	ErrIntervalCaptureAtLastWaypoint = "waypoint %d is the last waypoint and starts an interval capture, so it keeps capturing during return to home"
+	ErrUnsafeMissionName             = "mission name %q contains characters that are not valid in file names, use %q as the file name"
+	ErrTransitionalSpeedAboveCruise  = "global transitional speed %.1fm/s, flown from takeoff to the first waypoint and when resuming the route, is higher than the global cruise speed %.1fm/s"
+	ErrStaleGimbalPitch              = "waypoint %d: photos from here on use the gimbal pitch left by the %s at waypoint %d, which overrode the last set pitch; set the pitch again if that is not intended"
+	ErrMappingOverlapOutOfRange      = "mapping %s overlap %d%% is outside the accepted range [%d%%, %d%%]"
+	ErrLowMappingOverlap             = "mapping %s overlap %d%% is below %d%%, which leaves too little overlap for a reliable orthomosaic"
+	ErrFinishRCLostConflict          = "finish action %s conflicts with RC-lost action %s: %s"
+	ErrTooManyActionGroups           = "mission has %d action groups, more than the limit of %d"
+	ErrHeadingPOIOutOfRange          = "heading POI %f,%f is outside the latitude range [-90, 90] or the longitude range [-180, 180]"
+	ErrActionGroupIDsExhausted       = "mission has %d action groups, more than the %d IDs wpml:actionGroupId can number"
+	ErrActionGroupIDOutOfRange       = "waypoint %d was allocated action group ID %d, outside the 0 to %d range of wpml:actionGroupId"
+	ErrDuplicateActionGroupID        = "waypoint %d was allocated action group ID %d, which waypoint %d already uses"
+	ErrGimbalRotateModeRequired      = "waypoint %d: gimbalRotate sets a %s angle without a rotate mode, set GimbalRotateMode explicitly (%q is the safe default)"
+	ErrInvalidOrbit                  = "invalid orbit %s %v: %s"
+	ErrInvalidMetadataKey            = "metadata key %q must be 1 to %d letters, digits, '_', '-' or '.', starting with a letter or '_'"
+	ErrMetadataValueTooLong          = "metadata value of %q is %d characters, at most %d are allowed"
+	ErrInvalidMetadataValue          = "metadata value of %q contains characters that are not allowed in XML"
+	ErrInfeasibleVerticalSpeed       = "leg %d: %s at %.1fm/s, the limit of drone model %d is %.1fm/s"
+	ErrInfeasibleGimbalSlew          = "leg %d: gimbal pitch turns at %.1f°/s, the gimbal turns at most %.1f°/s"
+	ErrInfeasibleTurn                = "leg %d: turn at waypoint %d needs %.1fm/s² of lateral acceleration, at most %.1fm/s² is available; lower the speed or widen the turn"
+	ErrInfeasibleAcceleration        = "leg %d: changing from %.1fm/s to %.1fm/s over %.1fm needs %.1fm/s², at most %.1fm/s² is available"
+	ErrUnreachableLegSpeed           = "leg %d: reaching %.1fm/s within %.1fm needs %.1fm/s², at most %.1fm/s² is available, so the leg is flown slower than planned"
+	ErrDuplicatePayloadPosition      = "payload position %d is used by more than one payload"
+	ErrPayloadPositionUnsupported    = "drone %d has no payload position %d, available positions are %v"
+	ErrPayloadPositionIncompatible   = "payload %d cannot be mounted at position %d, supported positions are %v"
+	ErrTriggerActionMismatch         = "waypoint %d trigger type %s: %s"
+	ErrUnknownMergePolicy            = "unknown merge policy %q"
+	ErrMergeConflict                 = "cannot append missions, %s differs: %v and %v"
+	ErrParseGSPro                    = "failed to parse GS Pro mission: %w"
+	ErrInvalidGSProMission           = "GS Pro mission does not convert to a valid mission: %w"
+	ErrFirstTransitClimbTooSteep     = "the transit from the %.1fm takeoff security height to waypoint 0 at %.1fm climbs at %.1fm/s, above the %.1fm/s ascent limit of drone %d; lower the first waypoint, raise the safe height, move the takeoff point further away or reduce the transitional speed"
+	ErrInvalidRegion                 = "invalid region %s: %w"
+	ErrInvalidRingCoordinate         = "invalid coordinate %q, expected longitude,latitude[,altitude]"
+	ErrRingTooShort                  = "ring has %d distinct points, at least 3 are needed"
+	ErrWaypointOutsideRegion         = "waypoint %d at %.6f,%.6f is outside the allowed region"
+	ErrWaypointInRegionHole          = "waypoint %d at %.6f,%.6f is inside hole %d of the allowed region"
+	ErrUnknownActionTriggerType      = "waypoint %d action trigger type %q is not one of %v"
+	ErrActionTriggerParamRequired    = "waypoint %d action trigger %s needs a positive interval in %s"
+	ErrActionTriggerParamUnexpected  = "waypoint %d action trigger %s takes no parameter, got %g"
+	ErrTooManyWaypointsForUpload     = "mission has %d waypoints, above the threshold of %d; large missions upload slowly and can time out"
+	ErrMissionComplexityTooHigh      = "mission complexity score %.0f (%d waypoints plus %d actions) is above the threshold of %.0f; large missions upload slowly and can time out"
+	ErrWaypointBeyondStagingRange    = "waypoint %d is %.0fm from the staging point, beyond the %.0fm range"
+	ErrInvalidSpeedPolicy            = "invalid speed policy %.1fm/s: must be positive"
+	ErrSpeedAbovePolicy              = "%s speed %.1fm/s exceeds the %.1fm/s speed policy"
+	ErrMixedHeightModes              = "waypoints %v use a height mode other than the mission height mode %s"
+	ErrPhotoSettingsRequired         = "waypoint %d: %s captures without a lens selection, photo settings are required when the mission captures images unless each capture action sets its own lens index"
+	ErrCombinedYawOutOfRange         = "waypoint %d: %s points the gimbal %.1f° from the aircraft nose, outside payload %d gimbal yaw range [%.0f°, %.0f°]"
 )
 
 var (
@@ -63,4 +144,8 @@ var (
 	ErrActionGroupCannotBeNil       = errors.New("action group cannot be nil")
 	ErrWaylineDocumentCannotBeNil   = errors.New("wayline document cannot be nil")
 	ErrTemplateCannotBeNil          = errors.New("template cannot be nil")
+	ErrInvalidHeaderComment         = errors.New("header comment must not contain \"--\", which would end or break the XML comment")
+	ErrConflictingIDSources         = errors.New("set either ActionGroupIDAllocator or IDSource, not both")
+	ErrBatchSharedIDSource          = errors.New("ConvertBatch cannot share Render.ActionGroupIDAllocator or Render.IDSource between missions; set BatchOptions.ActionGroupIDAllocator or BatchOptions.IDSource")
+	ErrHeadingPOIRequired           = errors.New("aircraft yaw mode towardPOI requires a heading POI")
 )
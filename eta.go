@@ -0,0 +1,76 @@
+package wpml
+
+import (
+	"math"
+	"time"
+)
+
+// EstimatedDuration returns the estimated flight time from the first to the
+// last waypoint. Each leg is flown at the speed of the waypoint it starts from
+// over its straight-line length, including the height change, and the
+// aircraft dwells at each waypoint for the hover actions it runs there.
+// Acceleration, turns and the transit from takeoff are not modeled. The
+// converter writes it as the wpml:duration of the wayline.
+func (w *Waylines) EstimatedDuration() time.Duration {
+	seconds := 0.0
+	for i := range w.Waypoints {
+		seconds += w.dwellTime(i) + w.legTime(i)
+	}
+	return secondsToDuration(seconds)
+}
+
+// dwellTime returns the seconds the aircraft hovers at waypoint i.
+func (w *Waylines) dwellTime(i int) float64 {
+	seconds := 0.0
+	for _, action := range w.effectiveActions(w.Waypoints[i]) {
+		if hover, ok := action.Action.(*HoverAction); ok {
+			seconds += hover.HoverTime
+		}
+	}
+	return seconds
+}
+
+// legTime returns the seconds taken to fly from waypoint i to the next one,
+// or zero for the last waypoint.
+func (w *Waylines) legTime(i int) float64 {
+	if i >= len(w.Waypoints)-1 {
+		return 0
+	}
+	speed := w.waypointSpeed(w.Waypoints[i])
+	if speed <= 0 {
+		return 0
+	}
+	return w.legLength3D(i) / speed
+}
+
+// flightDistance returns the length in meters of the route from the first to
+// the last waypoint over the legs EstimatedDuration times, written as the
+// wpml:distance of the wayline.
+func (w *Waylines) flightDistance() float64 {
+	distance := 0.0
+	for i := 0; i < len(w.Waypoints)-1; i++ {
+		distance += w.legLength3D(i)
+	}
+	return distance
+}
+
+// legLength3D returns the length in meters of the leg from waypoint i to the
+// next one, including the height change.
+func (w *Waylines) legLength3D(i int) float64 {
+	from, to := w.Waypoints[i], w.Waypoints[i+1]
+	return math.Hypot(from.position().distanceTo(to.position()), w.waypointHeight(to)-w.waypointHeight(from))
+}
+
+// waypointHeight returns the height flown at waypoint in the mission height
+// mode. Heights that cannot be converted, which Validate rejects, are returned
+// as set.
+func (w *Waylines) waypointHeight(waypoint WaylinesWaypoint) float64 {
+	if height, err := w.missionHeight(waypoint); err == nil {
+		return height
+	}
+	return waypoint.Height
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
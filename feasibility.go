@@ -0,0 +1,176 @@
+package wpml
+
+import (
+	"fmt"
+	"math"
+)
+
+// Physical limits that FeasibilityReport assumes in addition to the
+// per-model DroneLimits.
+const (
+	// MaxGimbalSlewRate is the fastest gimbal pitch rotation, in °/s.
+	MaxGimbalSlewRate = 90.0
+	// MaxTurnAcceleration is the lateral acceleration, in m/s², the aircraft
+	// sustains in pass-type turns.
+	MaxTurnAcceleration = 3.0
+	// marginalFeasibilityRatio is the share of a limit above which a leg is
+	// reported as marginal.
+	marginalFeasibilityRatio = 0.8
+)
+
+type FeasibilitySeverity string
+
+const (
+	// FeasibilitySeverityMarginal marks legs that are flyable but close to a
+	// limit, or where the aircraft will not reach the planned speed.
+	FeasibilitySeverityMarginal FeasibilitySeverity = "marginal"
+	// FeasibilitySeverityInfeasible marks legs the aircraft cannot fly as
+	// planned.
+	FeasibilitySeverityInfeasible FeasibilitySeverity = "infeasible"
+)
+
+type FeasibilityConstraint string
+
+const (
+	FeasibilityConstraintVerticalSpeed  FeasibilityConstraint = "verticalSpeed"
+	FeasibilityConstraintGimbalSlewRate FeasibilityConstraint = "gimbalSlewRate"
+	FeasibilityConstraintTurnRadius     FeasibilityConstraint = "turnRadius"
+	FeasibilityConstraintAcceleration   FeasibilityConstraint = "acceleration"
+)
+
+// FeasibilityIssue is one constraint a leg violates or comes close to. Leg i
+// runs from waypoint i to waypoint i+1; turns are reported on the leg that
+// ends at the turning waypoint. Value and Limit are in the unit of the
+// constraint: m/s, °/s or m/s².
+type FeasibilityIssue struct {
+	Leg        int                   `json:"leg"`
+	Constraint FeasibilityConstraint `json:"constraint"`
+	Severity   FeasibilitySeverity   `json:"severity"`
+	Value      float64               `json:"value"`
+	Limit      float64               `json:"limit"`
+	Message    string                `json:"message"`
+}
+
+type FeasibilityReport struct {
+	Issues []FeasibilityIssue `json:"issues"`
+}
+
+// Feasible reports whether no leg is infeasible. Marginal issues do not
+// count.
+func (r FeasibilityReport) Feasible() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == FeasibilitySeverityInfeasible {
+			return false
+		}
+	}
+	return true
+}
+
+// FeasibilityReport checks every leg against the physical limits of the
+// aircraft and gimbal: the vertical speed needed to fly the leg at its
+// planned speed, the gimbal pitch rate of gimbalEvenlyRotate actions, the
+// lateral acceleration of pass-type turns, and whether the aircraft can
+// change between the speeds at either end of the leg at
+// ScheduleAcceleration. Legs above a limit are infeasible; legs above 80% of
+// one, or too short to reach their planned speed, are marginal. Issues are
+// ordered by leg. The mission is not modified.
+func (w *Waylines) FeasibilityReport() FeasibilityReport {
+	report := FeasibilityReport{Issues: []FeasibilityIssue{}}
+	if len(w.Waypoints) < 2 {
+		return report
+	}
+
+	limits := LimitsForDrone(w.DroneModel)
+	stops := w.sampleStops()
+	for i := 0; i < len(w.Waypoints)-1; i++ {
+		legTime := w.legTime(i)
+		if legTime <= 0 {
+			continue
+		}
+
+		climb := w.waypointHeight(w.Waypoints[i+1]) - w.waypointHeight(w.Waypoints[i])
+		if climb > 0 {
+			report.add(i, FeasibilityConstraintVerticalSpeed, climb/legTime, limits.MaxAscentSpeed,
+				func(value, limit float64) string {
+					return fmt.Sprintf(ErrInfeasibleVerticalSpeed, i, "climbs", value, w.DroneModel, limit)
+				})
+		} else if climb < 0 {
+			report.add(i, FeasibilityConstraintVerticalSpeed, -climb/legTime, limits.MaxDescentSpeed,
+				func(value, limit float64) string {
+					return fmt.Sprintf(ErrInfeasibleVerticalSpeed, i, "descends", value, w.DroneModel, limit)
+				})
+		}
+
+		if pitchChange := math.Abs(stops[i].legEndPitch - stops[i].pitch); pitchChange > 0 {
+			report.add(i, FeasibilityConstraintGimbalSlewRate, pitchChange/legTime, MaxGimbalSlewRate,
+				func(value, limit float64) string {
+					return fmt.Sprintf(ErrInfeasibleGimbalSlew, i, value, limit)
+				})
+		}
+
+		if turn := i + 1; turn < len(w.Waypoints)-1 && isPassTurnMode(w.effectiveTurnMode(w.Waypoints[turn])) {
+			if radius, err := w.turnDampingRadius(turn); err == nil && radius > 0 {
+				speed := w.throughSpeed(turn)
+				report.add(i, FeasibilityConstraintTurnRadius, speed*speed/radius, MaxTurnAcceleration,
+					func(value, limit float64) string {
+						return fmt.Sprintf(ErrInfeasibleTurn, i, turn, value, limit)
+					})
+			}
+		}
+
+		report.addAcceleration(w, i)
+	}
+	return report
+}
+
+// add records an issue when value exceeds the marginal share of limit.
+func (r *FeasibilityReport) add(leg int, constraint FeasibilityConstraint, value, limit float64, message func(value, limit float64) string) {
+	severity := FeasibilitySeverityInfeasible
+	switch {
+	case value > limit:
+	case value > limit*marginalFeasibilityRatio:
+		severity = FeasibilitySeverityMarginal
+	default:
+		return
+	}
+	r.Issues = append(r.Issues, FeasibilityIssue{
+		Leg:        leg,
+		Constraint: constraint,
+		Severity:   severity,
+		Value:      value,
+		Limit:      limit,
+		Message:    message(value, limit),
+	})
+}
+
+// addAcceleration checks that the aircraft can change from the speed it
+// passes waypoint i at to the one it passes the next waypoint at within the
+// leg, and whether it reaches the planned speed in between.
+func (r *FeasibilityReport) addAcceleration(w *Waylines, i int) {
+	entry, exit := w.throughSpeed(i), w.throughSpeed(i+1)
+	length := w.legLength3D(i)
+	required := math.Abs(exit*exit-entry*entry) / (2 * length)
+	message := func(value, limit float64) string {
+		return fmt.Sprintf(ErrInfeasibleAcceleration, i, entry, exit, length, value, limit)
+	}
+	if required > ScheduleAcceleration {
+		r.add(i, FeasibilityConstraintAcceleration, required, ScheduleAcceleration, message)
+		return
+	}
+
+	a := ScheduleAcceleration
+	cruise := w.waypointSpeed(w.Waypoints[i])
+	if peak := math.Sqrt((2*a*length + entry*entry + exit*exit) / 2); peak < cruise {
+		// The planned speed is out of reach: report the acceleration it
+		// would need as marginal rather than infeasible.
+		needed := (2*cruise*cruise - entry*entry - exit*exit) / (2 * length)
+		r.Issues = append(r.Issues, FeasibilityIssue{
+			Leg:        i,
+			Constraint: FeasibilityConstraintAcceleration,
+			Severity:   FeasibilitySeverityMarginal,
+			Value:      needed,
+			Limit:      a,
+			Message:    fmt.Sprintf(ErrUnreachableLegSpeed, i, cruise, length, needed, a),
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issuesFor(report FeasibilityReport, constraint FeasibilityConstraint) []FeasibilityIssue {
+	var issues []FeasibilityIssue
+	for _, issue := range report.Issues {
+		if issue.Constraint == constraint {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func TestFeasibilityReport_FeasibleMission(t *testing.T) {
+	report := sampleWaylines().FeasibilityReport()
+	assert.Empty(t, report.Issues)
+	assert.True(t, report.Feasible())
+}
+
+func TestFeasibilityReport_VerticalSpeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		climb    float64
+		severity FeasibilitySeverity
+	}{
+		{name: "Gentle climb", climb: 40},
+		{name: "Climb close to the limit", climb: 70, severity: FeasibilitySeverityMarginal},
+		{name: "Climb above the limit", climb: 100, severity: FeasibilitySeverityInfeasible},
+		{name: "Descent above the limit", climb: -100, severity: FeasibilitySeverityInfeasible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := sampleWaylines()
+			waylines.Waypoints[0].Height = 150
+			waylines.Waypoints[1].Height = 150 + tt.climb
+
+			issues := issuesFor(waylines.FeasibilityReport(), FeasibilityConstraintVerticalSpeed)
+			if tt.severity == "" {
+				assert.Empty(t, issues)
+				return
+			}
+			require.Len(t, issues, 1)
+			assert.Equal(t, 0, issues[0].Leg)
+			assert.Equal(t, tt.severity, issues[0].Severity)
+			assert.Contains(t, issues[0].Message, "leg 0:")
+		})
+	}
+}
+
+func TestFeasibilityReport_GimbalSlewRate(t *testing.T) {
+	waylines := waylinesAt("Gimbal", [2]float64{39.9000, 116.400}, [2]float64{39.9001, 116.400})
+	waylines.Waypoints[0].Actions = []ActionRequest{{
+		Type:   ActionTypeGimbalEvenlyRotate,
+		Action: &GimbalEvenlyRotateAction{GimbalPitchRotateAngle: -90},
+	}}
+
+	report := waylines.FeasibilityReport()
+	issues := issuesFor(report, FeasibilityConstraintGimbalSlewRate)
+	require.Len(t, issues, 1)
+	assert.Equal(t, FeasibilitySeverityInfeasible, issues[0].Severity)
+	assert.Greater(t, issues[0].Value, MaxGimbalSlewRate)
+	assert.False(t, report.Feasible())
+}
+
+func TestFeasibilityReport_TurnRadius(t *testing.T) {
+	waylines := waylinesAt("Turn", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400}, [2]float64{39.901, 116.401})
+	waylines.Waypoints[1].WaypointTurnMode = TurnModeToPointAndPassWithContinuityCurvature
+	waylines.Waypoints[1].TurnDampingRadius = 5
+
+	issues := issuesFor(waylines.FeasibilityReport(), FeasibilityConstraintTurnRadius)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 0, issues[0].Leg)
+	assert.Equal(t, FeasibilitySeverityInfeasible, issues[0].Severity)
+	assert.Contains(t, issues[0].Message, "turn at waypoint 1")
+
+	waylines.Waypoints[1].TurnDampingRadius = 100
+	assert.Empty(t, issuesFor(waylines.FeasibilityReport(), FeasibilityConstraintTurnRadius))
+}
+
+func TestFeasibilityReport_Acceleration(t *testing.T) {
+	waylines := waylinesAt("Acceleration", [2]float64{39.9000, 116.400}, [2]float64{39.9001, 116.400}, [2]float64{39.9101, 116.400})
+	waylines.Waypoints[1].WaypointTurnMode = TurnModeToPointAndPassWithContinuityCurvature
+
+	issues := issuesFor(waylines.FeasibilityReport(), FeasibilityConstraintAcceleration)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 0, issues[0].Leg)
+	assert.Equal(t, FeasibilitySeverityInfeasible, issues[0].Severity)
+
+	waylines.Waypoints[1].WaypointTurnMode = ""
+	issues = issuesFor(waylines.FeasibilityReport(), FeasibilityConstraintAcceleration)
+	require.Len(t, issues, 1)
+	assert.Equal(t, FeasibilitySeverityMarginal, issues[0].Severity)
+	assert.Contains(t, issues[0].Message, "flown slower than planned")
+}
+
+func TestFeasibilityReport_DoesNotMutate(t *testing.T) {
+	waylines := waylinesWithActionsAt("Pure", 4, 1, 2)
+	waylines.Waypoints[2].WaypointTurnMode = TurnModeCoordinateTurn
+	before := *waylines
+	before.Waypoints = append([]WaylinesWaypoint(nil), waylines.Waypoints...)
+
+	waylines.FeasibilityReport()
+	assert.Equal(t, before, *waylines)
+}
@@ -0,0 +1,57 @@
+package wpml
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const defaultFileName = "mission"
+
+// windowsReservedNames are device names Windows refuses as file names. They
+// are matched against the whole name, which SafeFileName returns without an
+// extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SafeFileName returns the mission name as a file name, without extension,
+// that is valid on Windows, macOS, Linux and the remote controller. Path
+// separators, reserved punctuation and control characters become underscores,
+// leading and trailing spaces and dots are dropped, and reserved device names
+// are prefixed with an underscore. Name itself is not changed and is still
+// written into the mission.
+func (w *Waylines) SafeFileName() string {
+	name := strings.Map(func(r rune) rune {
+		if isUnsafeFileNameRune(r) {
+			return '_'
+		}
+		return r
+	}, w.Name)
+	name = strings.Trim(name, " .")
+
+	if name == "" {
+		return defaultFileName
+	}
+	if windowsReservedNames[strings.ToUpper(name)] {
+		name = "_" + name
+	}
+	return name
+}
+
+func isUnsafeFileNameRune(r rune) bool {
+	return strings.ContainsRune(`/\:*?"<>|`, r) || unicode.IsControl(r)
+}
+
+func fileNameWarnings(w *Waylines) []Warning {
+	safe := w.SafeFileName()
+	if safe == w.Name {
+		return nil
+	}
+	return []Warning{{
+		Rule:    WarningRuleUnsafeFileName,
+		Message: fmt.Sprintf(ErrUnsafeMissionName, w.Name, safe),
+	}}
+}
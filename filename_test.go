@@ -0,0 +1,46 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "already safe", input: "Bridge Inspection 03", expected: "Bridge Inspection 03"},
+		{name: "path separators", input: "Site A/B\\C", expected: "Site A_B_C"},
+		{name: "reserved punctuation", input: `Pass 1: "north"?`, expected: "Pass 1_ _north__"},
+		{name: "control characters", input: "line\tbreak\n", expected: "line_break_"},
+		{name: "trailing dots and spaces", input: " survey.. ", expected: "survey"},
+		{name: "reserved device name", input: "con", expected: "_con"},
+		{name: "nothing left", input: " ... ", expected: "mission"},
+		{name: "unicode kept", input: "巡检任务", expected: "巡检任务"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := &Waylines{Name: tt.input}
+			assert.Equal(t, tt.expected, waylines.SafeFileName())
+			assert.Equal(t, tt.input, waylines.Name)
+		})
+	}
+}
+
+func TestFileNameWarning(t *testing.T) {
+	waylines := createValidWaylines("Tower 1/2")
+
+	warnings := waylines.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarningRuleUnsafeFileName, warnings[0].Rule)
+	assert.Contains(t, warnings[0].Message, `"Tower 1_2"`)
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	assert.NotNil(t, mission)
+}
@@ -0,0 +1,98 @@
+package wpml
+
+import (
+	"fmt"
+	"math"
+)
+
+// FirstTransitClimb describes the leg from the takeoff point, after climbing
+// to the takeoff security height, to the first waypoint.
+type FirstTransitClimb struct {
+	// StartHeight is the takeoff security height and EndHeight the first
+	// waypoint height, both in meters above the takeoff point.
+	StartHeight float64
+	EndHeight   float64
+	// Distance is the horizontal distance from the takeoff point to the first
+	// waypoint, in meters.
+	Distance float64
+	// Rate is the vertical speed, in m/s, of flying the leg in a straight line
+	// at the transitional speed.
+	Rate float64
+}
+
+// FirstTransitClimb returns the climb of the first transit leg. ok is false
+// when the climb cannot be worked out: without a takeoff reference point the
+// launch site is unknown, terrain-relative heights cannot be related to the
+// takeoff point without elevation data, and EGM96 heights need the takeoff
+// reference height. A first waypoint below the security height climbs at a
+// zero rate.
+func (w *Waylines) FirstTransitClimb() (climb FirstTransitClimb, ok bool) {
+	if len(w.Waypoints) == 0 || !w.hasTakeOffRefPoint() {
+		return FirstTransitClimb{}, false
+	}
+
+	first := w.Waypoints[0]
+	var reference float64
+	switch w.waypointHeightMode(0) {
+	case HeightModeRelativeToStartPoint:
+		reference = 0
+	case HeightModeEGM96:
+		reference = w.TakeOffRefPointHeight
+	default:
+		return FirstTransitClimb{}, false
+	}
+
+	climb = FirstTransitClimb{
+		StartHeight: w.takeOffSecurityHeight(),
+		EndHeight:   first.Height - reference,
+		Distance: LatLng{Latitude: w.TakeOffRefPointLatitude, Longitude: w.TakeOffRefPointLongitude}.
+			distanceTo(LatLng{Latitude: first.Latitude, Longitude: first.Longitude}),
+	}
+	if rise := climb.EndHeight - climb.StartHeight; rise > 0 {
+		climb.Rate = w.transitionalSpeed() * rise / math.Hypot(climb.Distance, rise)
+	}
+	return climb, true
+}
+
+// ValidateFirstTransitClimb returns an error when the first waypoint is so far
+// above the takeoff security height that reaching it at the transitional
+// speed needs a faster climb than the aircraft's ascent limit. The aircraft
+// then climbs at its limit and reaches the first waypoint late. Warnings
+// reports the same finding.
+func (w *Waylines) ValidateFirstTransitClimb() error {
+	climb, limit, ok := w.steepFirstTransit()
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf(ErrFirstTransitClimbTooSteep, climb.StartHeight, climb.EndHeight, climb.Rate, limit, w.DroneModel)
+}
+
+func firstTransitClimbWarnings(w *Waylines) []Warning {
+	climb, limit, ok := w.steepFirstTransit()
+	if !ok {
+		return nil
+	}
+	return []Warning{{
+		Rule:            WarningRuleFirstTransitClimb,
+		WaypointIndices: []int{0},
+		Message:         fmt.Sprintf(ErrFirstTransitClimbTooSteep, climb.StartHeight, climb.EndHeight, climb.Rate, limit, w.DroneModel),
+	}}
+}
+
+// steepFirstTransit returns the first transit climb and the ascent limit when
+// the climb exceeds the limit.
+func (w *Waylines) steepFirstTransit() (climb FirstTransitClimb, limit float64, ok bool) {
+	climb, ok = w.FirstTransitClimb()
+	limit = LimitsForDrone(w.DroneModel).MaxAscentSpeed
+	if !ok || climb.Rate <= limit {
+		return FirstTransitClimb{}, 0, false
+	}
+	return climb, limit, true
+}
+
+func (w *Waylines) takeOffSecurityHeight() float64 {
+	if w.SafeHeight == 0 {
+		return 20
+	}
+	return w.SafeHeight
+}
@@ -0,0 +1,82 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func firstTransitWaylines(height float64) *Waylines {
+	waylines := waylinesAt("First Transit", [2]float64{39.901, 116.400}, [2]float64{39.902, 116.400})
+	waylines.TakeOffRefPointLatitude = 39.900
+	waylines.TakeOffRefPointLongitude = 116.400
+	waylines.SafeHeight = 20
+	waylines.GlobalTransitionalSpeed = 10
+	waylines.Waypoints[0].Height = height
+	return waylines
+}
+
+func TestFirstTransitClimb(t *testing.T) {
+	climb, ok := firstTransitWaylines(120).FirstTransitClimb()
+	require.True(t, ok)
+	assert.Equal(t, 20.0, climb.StartHeight)
+	assert.Equal(t, 120.0, climb.EndHeight)
+	assert.InDelta(t, 111.2, climb.Distance, 0.1)
+	assert.InDelta(t, 6.7, climb.Rate, 0.05)
+
+	climb, ok = firstTransitWaylines(15).FirstTransitClimb()
+	require.True(t, ok)
+	assert.Zero(t, climb.Rate, "descending to the first waypoint")
+
+	egm96 := firstTransitWaylines(160)
+	egm96.HeightType = HeightModeEGM96
+	egm96.TakeOffRefPointHeight = 40
+	climb, ok = egm96.FirstTransitClimb()
+	require.True(t, ok)
+	assert.Equal(t, 120.0, climb.EndHeight)
+
+	noTakeoffPoint := firstTransitWaylines(120)
+	noTakeoffPoint.TakeOffRefPointLatitude = 0
+	noTakeoffPoint.TakeOffRefPointLongitude = 0
+	_, ok = noTakeoffPoint.FirstTransitClimb()
+	assert.False(t, ok)
+
+	terrain := firstTransitWaylines(120)
+	terrain.HeightType = HeightModeAboveGroundLevel
+	_, ok = terrain.FirstTransitClimb()
+	assert.False(t, ok)
+}
+
+func TestValidateFirstTransitClimb(t *testing.T) {
+	tests := []struct {
+		name    string
+		height  float64
+		speed   float64
+		wantErr bool
+	}{
+		{name: "Gentle climb", height: 60, speed: 10},
+		{name: "Steep climb", height: 120, speed: 10, wantErr: true},
+		{name: "Steep climb flown slower", height: 120, speed: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := firstTransitWaylines(tt.height)
+			waylines.GlobalTransitionalSpeed = tt.speed
+
+			err := waylines.ValidateFirstTransitClimb()
+			warnings := firstTransitClimbWarnings(waylines)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Empty(t, warnings)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "climbs at 6.7m/s, above the 6.0m/s ascent limit")
+			require.Len(t, warnings, 1)
+			assert.Equal(t, WarningRuleFirstTransitClimb, warnings[0].Rule)
+			assert.Equal(t, []int{0}, warnings[0].WaypointIndices)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package wpml
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	DefaultTestMissionSquareSize = 20.0
+	DefaultTestMissionHeight     = 15.0
+	DefaultTestMissionSpeed      = 3.0
+)
+
+// TestMissionConfig parameterizes GenerateTestMissionWithConfig. Zero fields
+// use the DefaultTestMission* values.
+type TestMissionConfig struct {
+	// SquareSize is the side length of the square, in meters.
+	SquareSize float64
+	// Height is the flight height relative to the takeoff point, in meters.
+	// Heights outside the range the drone and relative height mode accept
+	// are clamped to it.
+	Height float64
+	// Speed is the flight speed, in m/s. Speeds above the drone's maximum
+	// are lowered to it.
+	Speed float64
+}
+
+// GenerateTestMission builds the standard pre-flight system check: a small
+// square centered on home, flown low and slow, with a photo at each corner and
+// a return to home at the end.
+func GenerateTestMission(home LatLng, drone DroneModel, payload PayloadModel) *Waylines {
+	return GenerateTestMissionWithConfig(home, drone, payload, TestMissionConfig{})
+}
+
+func GenerateTestMissionWithConfig(home LatLng, drone DroneModel, payload PayloadModel, config TestMissionConfig) *Waylines {
+	size := config.SquareSize
+	if size <= 0 {
+		size = DefaultTestMissionSquareSize
+	}
+	height := config.Height
+	if height <= 0 {
+		height = DefaultTestMissionHeight
+	}
+	limits := LimitsForDrone(drone)
+	maxHeight := waypointHeightRanges[HeightModeRelativeToStartPoint].max
+	height = math.Min(math.Max(height, limits.MinHeight), maxHeight)
+	speed := config.Speed
+	if speed <= 0 {
+		speed = DefaultTestMissionSpeed
+	}
+	speed = math.Min(speed, limits.MaxSpeed)
+
+	half := size / 2
+	corners := []LatLng{
+		home.offset(half, -half),
+		home.offset(half, half),
+		home.offset(-half, half),
+		home.offset(-half, -half),
+	}
+
+	waypoints := make([]WaylinesWaypoint, len(corners))
+	for i, corner := range corners {
+		waypoints[i] = WaylinesWaypoint{
+			Latitude:    corner.Latitude,
+			Longitude:   corner.Longitude,
+			Height:      height,
+			Speed:       speed,
+			TriggerType: TriggerTypeReachPoint,
+			Actions: []ActionRequest{
+				{
+					Type:   ActionTypeTakePhoto,
+					Action: &TakePhotoAction{PayloadPositionIndex: PayloadPosition0},
+				},
+			},
+		}
+	}
+
+	safeHeight := missionSafeHeight(height)
+
+	return &Waylines{
+		Name:                     "Test Mission",
+		Description:              "Pre-flight system check",
+		DroneModel:               drone,
+		PayloadModel:             payload,
+		TemplateType:             TemplateTypeWaypoint,
+		GlobalHeight:             height,
+		GlobalSpeed:              speed,
+		PhotoSettings:            []string{"wide"},
+		FinishAction:             FinishActionGoHome,
+		HeightType:               HeightModeRelativeToStartPoint,
+		ClimbMode:                "vertical",
+		SafeHeight:               safeHeight,
+		GlobalRTHHeight:          safeHeight,
+		AircraftYawMode:          "followWayline",
+		GimbalPitchMode:          "usePointSetting",
+		GlobalTransitionalSpeed:  speed,
+		TakeOffRefPointLatitude:  home.Latitude,
+		TakeOffRefPointLongitude: home.Longitude,
+		Waypoints:                waypoints,
+	}
+}
+
+// OrbitMission builds a circular point-of-interest mission around center:
+// pointCount waypoints per revolution on a circle of radius meters, flown at
+// height meters above the takeoff point and speed m/s for the given number of
+// revolutions. The aircraft faces the center throughout, the gimbal is pitched
+// at the center's base at the first waypoint, and a photo is taken at every
+// waypoint but the last, which closes the orbit. Height and speed are checked
+// against the drone's limits, the radius against minOrbitRadius, and the
+// result is validated before it is returned.
+func OrbitMission(center LatLng, radius, height, speed float64, revolutions float64, pointCount int, drone DroneModel, payload PayloadModel) (*Waylines, error) {
+	limits := LimitsForDrone(drone)
+	switch {
+	case radius <= 0:
+		return nil, fmt.Errorf(ErrInvalidOrbit, "radius", radius, "must be positive")
+	case height < limits.MinHeight:
+		return nil, fmt.Errorf(ErrInvalidOrbit, "height", height, fmt.Sprintf("below the %.1fm minimum of drone model %d", limits.MinHeight, drone))
+	case speed <= 0 || speed > limits.MaxSpeed:
+		return nil, fmt.Errorf(ErrInvalidOrbit, "speed", speed, fmt.Sprintf("outside (0, %.1f]m/s for drone model %d", limits.MaxSpeed, drone))
+	case revolutions <= 0:
+		return nil, fmt.Errorf(ErrInvalidOrbit, "revolutions", revolutions, "must be positive")
+	case pointCount < 3:
+		return nil, fmt.Errorf(ErrInvalidOrbit, "point count", pointCount, "must be at least 3")
+	}
+	if minRadius := minOrbitRadius(speed, pointCount); radius < minRadius {
+		return nil, fmt.Errorf(ErrInvalidOrbit, "radius", radius, fmt.Sprintf("below the %.1fm minimum for %g m/s with %d points per revolution", minRadius, speed, pointCount))
+	}
+
+	photos := int(math.Round(revolutions * float64(pointCount)))
+	step := 2 * math.Pi / float64(pointCount)
+	gimbalPitch := -math.Atan2(height, radius) * 180 / math.Pi
+
+	waypoints := make([]WaylinesWaypoint, photos+1)
+	for i := range waypoints {
+		angle := float64(i) * step
+		position := center.offset(radius*math.Cos(angle), radius*math.Sin(angle))
+		waypoints[i] = WaylinesWaypoint{
+			Latitude:    position.Latitude,
+			Longitude:   position.Longitude,
+			Height:      height,
+			Speed:       speed,
+			TriggerType: TriggerTypeReachPoint,
+		}
+		if i < photos {
+			waypoints[i].Actions = []ActionRequest{
+				{
+					Type:   ActionTypeTakePhoto,
+					Action: &TakePhotoAction{PayloadPositionIndex: PayloadPosition0},
+				},
+			}
+		}
+	}
+	waypoints[0].Actions = append([]ActionRequest{
+		{
+			Type: ActionTypeGimbalRotate,
+			Action: &GimbalRotateAction{
+				PayloadPositionIndex:    PayloadPosition0,
+				GimbalHeadingYawBase:    GimbalHeadingYawBaseAircraft,
+				GimbalRotateMode:        GimbalRotateModeAbsoluteAngle,
+				GimbalPitchRotateEnable: true,
+				GimbalPitchRotateAngle:  gimbalPitch,
+			},
+		},
+	}, waypoints[0].Actions...)
+
+	safeHeight := missionSafeHeight(height)
+
+	waylines := &Waylines{
+		Name:                    "Orbit Mission",
+		Description:             fmt.Sprintf("%g revolutions at %gm radius", revolutions, radius),
+		DroneModel:              drone,
+		PayloadModel:            payload,
+		TemplateType:            TemplateTypeWaypoint,
+		GlobalHeight:            height,
+		GlobalSpeed:             speed,
+		PhotoSettings:           []string{"wide"},
+		FinishAction:            FinishActionGoHome,
+		HeightType:              HeightModeRelativeToStartPoint,
+		ClimbMode:               "vertical",
+		SafeHeight:              safeHeight,
+		GlobalRTHHeight:         safeHeight,
+		AircraftYawMode:         HeadingModeTowardPOI,
+		HeadingPOI:              &center,
+		GimbalPitchMode:         "usePointSetting",
+		GlobalTransitionalSpeed: speed,
+		Waypoints:               waypoints,
+	}
+	if err := waylines.Validate(); err != nil {
+		return nil, err
+	}
+	return waylines, nil
+}
+
+// minOrbitRadius returns the smallest orbit radius in meters that leaves room
+// for the turns at speed: each chord between neighboring waypoints must hold
+// the RecommendedTurnDamping distance of the turns at both of its ends.
+func minOrbitRadius(speed float64, pointCount int) float64 {
+	damping := math.Max(speed*turnDampingSeconds, minTurnDampingDist)
+	return damping / math.Sin(math.Pi/float64(pointCount))
+}
+
+// missionSafeHeight returns the safe height of a generated mission flown at
+// height, kept within the 20-200 m range SafeHeight accepts.
+func missionSafeHeight(height float64) float64 {
+	return math.Min(math.Max(20, height), 200)
+}
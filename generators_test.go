@@ -0,0 +1,162 @@
+package wpml
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTestMission(t *testing.T) {
+	home := LatLng{Latitude: 39.9042, Longitude: 116.4074}
+
+	waylines := GenerateTestMission(home, DroneM3DSeries, PayloadMatrice3TDCamera)
+	require.NoError(t, waylines.Validate())
+
+	require.Len(t, waylines.Waypoints, 4)
+	assert.Equal(t, FinishActionGoHome, waylines.FinishAction)
+	for i, wp := range waylines.Waypoints {
+		assert.Equal(t, DefaultTestMissionHeight, wp.Height)
+		require.Len(t, wp.Actions, 1)
+		assert.Equal(t, ActionTypeTakePhoto, wp.Actions[0].Type)
+
+		corner := wp.position()
+		next := waylines.Waypoints[(i+1)%4]
+		assert.InDelta(t, DefaultTestMissionSquareSize/math.Sqrt2, home.distanceTo(corner), 0.01)
+		assert.InDelta(t, DefaultTestMissionSquareSize, corner.distanceTo(next.position()), 0.01)
+	}
+
+	_, err := ConvertWaylinesToWPMLMission(waylines)
+	assert.NoError(t, err)
+}
+
+func TestGenerateTestMissionWithConfig(t *testing.T) {
+	home := LatLng{Latitude: 46.55, Longitude: 8.56}
+
+	waylines := GenerateTestMissionWithConfig(home, DroneM30, PayloadM30Camera, TestMissionConfig{
+		SquareSize: 40,
+		Height:     2,
+		Speed:      5,
+	})
+	require.NoError(t, waylines.Validate())
+
+	minHeight := LimitsForDrone(DroneM30).MinHeight
+	for _, wp := range waylines.Waypoints {
+		assert.Equal(t, minHeight, wp.Height, "height is raised to the drone minimum")
+		assert.Equal(t, 5.0, wp.Speed)
+	}
+
+	first := waylines.Waypoints[0].position()
+	second := waylines.Waypoints[1].position()
+	assert.InDelta(t, 40.0, first.distanceTo(second), 0.01)
+}
+
+func TestGenerateTestMissionWithConfig_HighAltitude(t *testing.T) {
+	home := LatLng{Latitude: 39.9042, Longitude: 116.4074}
+
+	waylines := GenerateTestMissionWithConfig(home, DroneM3DSeries, PayloadMatrice3TDCamera, TestMissionConfig{Height: 250})
+	require.NoError(t, waylines.Validate())
+	assert.Equal(t, 200.0, waylines.SafeHeight)
+
+	waylines = GenerateTestMissionWithConfig(home, DroneM3DSeries, PayloadMatrice3TDCamera, TestMissionConfig{Height: 2000, Speed: 40})
+	require.NoError(t, waylines.Validate())
+	for _, wp := range waylines.Waypoints {
+		assert.Equal(t, 500.0, wp.Height, "height is lowered to the relative mode maximum")
+		assert.Equal(t, LimitsForDrone(DroneM3DSeries).MaxSpeed, wp.Speed)
+	}
+}
+
+func TestLimitsForDrone_UnknownModel(t *testing.T) {
+	assert.Equal(t, defaultDroneLimits, LimitsForDrone(DroneModel(1)))
+}
+
+func TestOrbitMission(t *testing.T) {
+	center := LatLng{Latitude: 39.9042, Longitude: 116.4074}
+
+	waylines, err := OrbitMission(center, 40, 60, 5, 1.5, 12, DroneM3DSeries, PayloadMatrice3TDCamera)
+	require.NoError(t, err)
+
+	require.Len(t, waylines.Waypoints, 19)
+	assert.Equal(t, HeadingModeTowardPOI, waylines.AircraftYawMode)
+	assert.Equal(t, center, *waylines.HeadingPOI)
+	for i, wp := range waylines.Waypoints {
+		assert.InDelta(t, 40, center.distanceTo(wp.position()), 0.01)
+		if i < 18 {
+			assert.Equal(t, ActionTypeTakePhoto, wp.Actions[len(wp.Actions)-1].Type)
+		} else {
+			assert.Empty(t, wp.Actions)
+		}
+	}
+	assert.Equal(t, 18, waylines.PhotoCount())
+
+	gimbal, ok := waylines.Waypoints[0].Actions[0].Action.(*GimbalRotateAction)
+	require.True(t, ok)
+	assert.InDelta(t, -56.31, gimbal.GimbalPitchRotateAngle, 0.01)
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	heading := mission.Template.Document.Folders[0].GlobalWaypointHeadingParam
+	assert.Equal(t, HeadingModeTowardPOI, heading.WaypointHeadingMode)
+	assert.Equal(t, "39.904200,116.407400,0.000000", *heading.WaypointPoiPoint)
+	placemark := mission.Waylines.Document.Folders[0].Placemarks[3]
+	assert.Equal(t, HeadingModeTowardPOI, placemark.WaypointHeadingParam.WaypointHeadingMode)
+}
+
+func TestOrbitMission_AboveMaxSafeHeight(t *testing.T) {
+	center := LatLng{Latitude: 39.9042, Longitude: 116.4074}
+
+	waylines, err := OrbitMission(center, 50, 250, 5, 1, 12, DroneM350RTK, PayloadH20T)
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, waylines.SafeHeight)
+	assert.Equal(t, 250.0, waylines.Waypoints[0].Height)
+}
+
+func TestOrbitMission_InvalidParameters(t *testing.T) {
+	center := LatLng{Latitude: 39.9042, Longitude: 116.4074}
+
+	tests := []struct {
+		name        string
+		radius      float64
+		height      float64
+		speed       float64
+		revolutions float64
+		points      int
+		expectError string
+	}{
+		{name: "Zero radius", radius: 0, height: 60, speed: 5, revolutions: 1, points: 12, expectError: "invalid orbit radius 0"},
+		{name: "Below minimum height", radius: 40, height: 2, speed: 5, revolutions: 1, points: 12, expectError: "invalid orbit height 2"},
+		{name: "Above maximum speed", radius: 40, height: 60, speed: 20, revolutions: 1, points: 12, expectError: "invalid orbit speed 20"},
+		{name: "No revolutions", radius: 40, height: 60, speed: 5, revolutions: 0, points: 12, expectError: "invalid orbit revolutions 0"},
+		{name: "Too few points", radius: 40, height: 60, speed: 5, revolutions: 1, points: 2, expectError: "invalid orbit point count 2"},
+		{name: "Radius too tight for speed", radius: 20, height: 60, speed: 10, revolutions: 1, points: 12, expectError: "invalid orbit radius 20: below the 38.6m minimum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OrbitMission(center, tt.radius, tt.height, tt.speed, tt.revolutions, tt.points, DroneM3DSeries, PayloadMatrice3TDCamera)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectError)
+		})
+	}
+}
+
+func TestValidateHeadingPOI(t *testing.T) {
+	waylines := createValidWaylines("POI")
+	waylines.AircraftYawMode = HeadingModeTowardPOI
+	assert.ErrorIs(t, waylines.Validate(), ErrHeadingPOIRequired)
+
+	waylines.HeadingPOI = &LatLng{Latitude: 39.9, Longitude: 116.4}
+	assert.NoError(t, waylines.Validate())
+
+	for _, poi := range []LatLng{{Latitude: 91, Longitude: 116.4}, {Latitude: 39.9, Longitude: -180.5}, {Latitude: math.NaN(), Longitude: 116.4}} {
+		waylines.HeadingPOI = &poi
+		err := waylines.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "heading POI")
+	}
+
+	waylines.AircraftYawMode = HeadingModeFollowWayline
+	waylines.HeadingPOI = &LatLng{Latitude: 39.9, Longitude: 190}
+	assert.Error(t, waylines.Validate(), "a POI is written whenever it is set")
+}
@@ -0,0 +1,75 @@
+package wpml
+
+import "math"
+
+const earthRadius = 6371000.0
+
+type LatLng struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// offset returns the point northMeters north and eastMeters east of p.
+func (p LatLng) offset(northMeters, eastMeters float64) LatLng {
+	latitude, longitude := newLocalProjection(p.Latitude, p.Longitude).unproject(eastMeters, northMeters)
+	return LatLng{Latitude: latitude, Longitude: longitude}
+}
+
+// distanceTo returns the horizontal distance in meters from p to q.
+func (p LatLng) distanceTo(q LatLng) float64 {
+	x, y := newLocalProjection(p.Latitude, p.Longitude).project(q.Latitude, q.Longitude)
+	return math.Hypot(x, y)
+}
+
+// bearingTo returns the initial heading in degrees, clockwise from north in
+// [-180, 180], from p to q.
+func (p LatLng) bearingTo(q LatLng) float64 {
+	x, y := newLocalProjection(p.Latitude, p.Longitude).project(q.Latitude, q.Longitude)
+	return math.Atan2(x, y) * 180 / math.Pi
+}
+
+// localProjection is an equirectangular projection around a reference point.
+// It is accurate to well under a meter over the few kilometers a waypoint
+// mission spans, which is all the geometric checks need.
+type localProjection struct {
+	refLatitude  float64
+	refLongitude float64
+	cosLatitude  float64
+}
+
+func newLocalProjection(latitude, longitude float64) localProjection {
+	return localProjection{
+		refLatitude:  latitude,
+		refLongitude: longitude,
+		cosLatitude:  math.Cos(latitude * math.Pi / 180),
+	}
+}
+
+func (p localProjection) project(latitude, longitude float64) (x, y float64) {
+	x = (longitude - p.refLongitude) * math.Pi / 180 * earthRadius * p.cosLatitude
+	y = (latitude - p.refLatitude) * math.Pi / 180 * earthRadius
+	return x, y
+}
+
+func (p localProjection) unproject(x, y float64) (latitude, longitude float64) {
+	latitude = p.refLatitude + y/earthRadius*180/math.Pi
+	longitude = p.refLongitude + x/(earthRadius*p.cosLatitude)*180/math.Pi
+	return latitude, longitude
+}
+
+// segmentIntersection reports whether segments p1-p2 and p3-p4 cross and, if
+// so, the parameters t along p1-p2 and u along p3-p4 at which they do.
+// Collinear overlaps are not reported.
+func segmentIntersection(x1, y1, x2, y2, x3, y3, x4, y4 float64) (t, u float64, ok bool) {
+	denominator := (x2-x1)*(y4-y3) - (y2-y1)*(x4-x3)
+	if denominator == 0 {
+		return 0, 0, false
+	}
+
+	t = ((x3-x1)*(y4-y3) - (y3-y1)*(x4-x3)) / denominator
+	u = ((x3-x1)*(y2-y1) - (y3-y1)*(x2-x1)) / denominator
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return 0, 0, false
+	}
+	return t, u, true
+}
@@ -0,0 +1,86 @@
+package wpml
+
+import "fmt"
+
+// DefaultStalePitchRun is the number of consecutive photo waypoints on a stale
+// gimbal pitch that Warnings reports.
+const DefaultStalePitchRun = 3
+
+// StaleGimbalPitch looks for photos taken on a possibly stale gimbal pitch in
+// usePointSetting missions, where the gimbal holds its last angle until the
+// next explicit setting. Shoot and panorama actions aim the gimbal for their
+// own capture and leave it there, so takePhoto actions after them no longer
+// use the pitch last set with gimbalRotate or gimbalEvenlyRotate. When at
+// least minRun consecutive photo waypoints follow such an action without the
+// pitch being set again, it returns the first of those waypoints and the
+// waypoint of the action that moved the gimbal; otherwise ok is false. A
+// waypoint that takes no photo ends the run.
+func (w *Waylines) StaleGimbalPitch(minRun int) (first, changedAt int, ok bool) {
+	if w.GimbalPitchMode != "usePointSetting" {
+		return 0, 0, false
+	}
+
+	changedAt, run := -1, 0
+	for i, wp := range w.Waypoints {
+		captured := false
+		for _, action := range wp.Actions {
+			switch {
+			case setsGimbalPitch(action):
+				changedAt, run = -1, 0
+			case movesGimbalForCapture(action.Type):
+				changedAt, run = i, 0
+			case action.Type == ActionTypeTakePhoto && changedAt >= 0:
+				captured = true
+			}
+		}
+		if !captured {
+			run = 0
+			continue
+		}
+		if run == 0 {
+			first = i
+		}
+		run++
+		if run >= minRun {
+			return first, changedAt, true
+		}
+	}
+	return 0, 0, false
+}
+
+func setsGimbalPitch(action ActionRequest) bool {
+	switch a := action.Action.(type) {
+	case *GimbalRotateAction:
+		return a.GimbalPitchRotateEnable && a.GimbalRotateMode == GimbalRotateModeAbsoluteAngle
+	case *GimbalEvenlyRotateAction:
+		return true
+	}
+	return false
+}
+
+func movesGimbalForCapture(actionType string) bool {
+	switch actionType {
+	case ActionTypeOrientedShoot, ActionTypeAccurateShoot, ActionTypePanoShot:
+		return true
+	default:
+		return false
+	}
+}
+
+func staleGimbalPitchWarnings(w *Waylines) []Warning {
+	first, changedAt, ok := w.StaleGimbalPitch(DefaultStalePitchRun)
+	if !ok {
+		return nil
+	}
+	var changedBy string
+	for _, action := range w.Waypoints[changedAt].Actions {
+		if movesGimbalForCapture(action.Type) {
+			changedBy = action.Type
+		}
+	}
+	return []Warning{{
+		Rule:            WarningRuleStaleGimbalPitch,
+		WaypointIndices: []int{first},
+		Message:         fmt.Sprintf(ErrStaleGimbalPitch, first, changedBy, changedAt),
+	}}
+}
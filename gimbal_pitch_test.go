@@ -0,0 +1,140 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gimbalPitchAction(mode string) ActionRequest {
+	return ActionRequest{
+		Type: ActionTypeGimbalRotate,
+		Action: &GimbalRotateAction{
+			GimbalHeadingYawBase:    GimbalHeadingYawBaseNorth,
+			GimbalRotateMode:        mode,
+			GimbalPitchRotateEnable: true,
+			GimbalPitchRotateAngle:  -90,
+		},
+	}
+}
+
+func panoAction() ActionRequest {
+	return ActionRequest{Type: ActionTypePanoShot, Action: &PanoShotAction{}}
+}
+
+func TestStaleGimbalPitch(t *testing.T) {
+	tests := []struct {
+		name          string
+		pitchMode     string
+		actions       map[int][]ActionRequest
+		expectOK      bool
+		expectFirst   int
+		expectChanged int
+	}{
+		{
+			name:      "Pitch set once and never overridden",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				0: {gimbalPitchAction(GimbalRotateModeAbsoluteAngle), photoAction()},
+				1: {photoAction()}, 2: {photoAction()}, 3: {photoAction()},
+			},
+		},
+		{
+			name:      "Panorama overrides the pitch before a run of photos",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				0: {gimbalPitchAction(GimbalRotateModeAbsoluteAngle), photoAction()},
+				1: {panoAction()},
+				2: {photoAction()}, 3: {photoAction()}, 4: {photoAction()},
+			},
+			expectOK:      true,
+			expectFirst:   2,
+			expectChanged: 1,
+		},
+		{
+			name:      "Pitch set again after the panorama",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				1: {panoAction()},
+				2: {photoAction()},
+				3: {gimbalPitchAction(GimbalRotateModeAbsoluteAngle), photoAction()},
+				4: {photoAction()}, 5: {photoAction()},
+			},
+		},
+		{
+			name:      "Relative rotation does not restore a known pitch",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				1: {panoAction()},
+				2: {gimbalPitchAction(GimbalRotateModeRelativeAngle), photoAction()},
+				3: {photoAction()}, 4: {photoAction()},
+			},
+			expectOK:      true,
+			expectFirst:   2,
+			expectChanged: 1,
+		},
+		{
+			name:      "Run shorter than the threshold",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				1: {panoAction()},
+				2: {photoAction()}, 3: {photoAction()},
+			},
+		},
+		{
+			name:      "Waypoint without a photo breaks the run",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				1: {panoAction()},
+				2: {photoAction()}, 3: {photoAction()},
+				5: {photoAction()},
+			},
+		},
+		{
+			name:      "Consecutive run after a gap",
+			pitchMode: "usePointSetting",
+			actions: map[int][]ActionRequest{
+				0: {panoAction()},
+				1: {photoAction()},
+				3: {photoAction()}, 4: {photoAction()}, 5: {photoAction()},
+			},
+			expectOK:      true,
+			expectFirst:   3,
+			expectChanged: 0,
+		},
+		{
+			name:      "Manual pitch mode is not checked",
+			pitchMode: "manual",
+			actions: map[int][]ActionRequest{
+				1: {panoAction()},
+				2: {photoAction()}, 3: {photoAction()}, 4: {photoAction()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Gimbal Pitch", 6)
+			waylines.GimbalPitchMode = tt.pitchMode
+			for i, actions := range tt.actions {
+				waylines.Waypoints[i].Actions = actions
+			}
+
+			first, changedAt, ok := waylines.StaleGimbalPitch(DefaultStalePitchRun)
+			assert.Equal(t, tt.expectOK, ok)
+			if tt.expectOK {
+				assert.Equal(t, tt.expectFirst, first)
+				assert.Equal(t, tt.expectChanged, changedAt)
+			}
+
+			var warned bool
+			for _, warning := range waylines.Warnings() {
+				if warning.Rule == WarningRuleStaleGimbalPitch {
+					warned = true
+					assert.Equal(t, []int{tt.expectFirst}, warning.WaypointIndices)
+				}
+			}
+			assert.Equal(t, tt.expectOK, warned)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package wpml
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// gsProMission is the JSON export of a DJI GS Pro waypoint mission, which
+// mirrors the Mobile SDK waypoint mission model.
+type gsProMission struct {
+	Name                      string           `json:"name"`
+	AutoFlightSpeed           float64          `json:"autoFlightSpeed"`
+	FinishedAction            string           `json:"finishedAction"`
+	HeadingMode               string           `json:"headingMode"`
+	FlightPathMode            string           `json:"flightPathMode"`
+	RotateGimbalPitch         bool             `json:"rotateGimbalPitch"`
+	ExitMissionOnRCSignalLost bool             `json:"exitMissionOnRCSignalLost"`
+	RepeatTimes               int              `json:"repeatTimes"`
+	PointOfInterest           *gsProCoordinate `json:"pointOfInterest"`
+	Waypoints                 []gsProWaypoint  `json:"waypoints"`
+}
+
+type gsProCoordinate struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type gsProWaypoint struct {
+	Coordinate                 gsProCoordinate `json:"coordinate"`
+	Altitude                   float64         `json:"altitude"`
+	Heading                    float64         `json:"heading"`
+	Speed                      float64         `json:"speed"`
+	CornerRadiusInMeters       float64         `json:"cornerRadiusInMeters"`
+	TurnMode                   string          `json:"turnMode"`
+	GimbalPitch                float64         `json:"gimbalPitch"`
+	ShootPhotoTimeInterval     float64         `json:"shootPhotoTimeInterval"`
+	ShootPhotoDistanceInterval float64         `json:"shootPhotoDistanceInterval"`
+	Actions                    []gsProAction   `json:"waypointActions"`
+}
+
+type gsProAction struct {
+	ActionType  string  `json:"actionType"`
+	ActionParam float64 `json:"actionParam"`
+}
+
+var gsProFinishActions = map[string]FinishAction{
+	"noAction":        FinishActionNoAction,
+	"goHome":          FinishActionGoHome,
+	"autoLand":        FinishActionAutoLand,
+	"goFirstWaypoint": FinishActionGotoFirstWaypoint,
+}
+
+// WaylinesFromGSPro converts a DJI GS Pro waypoint mission export. It is
+// ImportGSPro without the list of features that did not carry over.
+func WaylinesFromGSPro(data []byte) (*Waylines, error) {
+	waylines, _, err := ImportGSPro(data)
+	return waylines, err
+}
+
+// ImportGSPro converts a DJI GS Pro waypoint mission export and validates the
+// result. GS Pro missions were flown with aircraft that WPML does not support,
+// so the mission is set up for the DroneM3Series with the
+// PayloadMavic3ECamera; change DroneModel and PayloadModel before converting
+// for other aircraft.
+//
+// Altitudes are relative to the takeoff point, as in GS Pro. Stay, shoot
+// photo, record, rotate aircraft and gimbal pitch actions become their WPML
+// counterparts and still run once on arrival. A photo interval becomes an
+// IntervalCapture on the leg to the next waypoint, and curved paths become
+// coordinated turns with the GS Pro corner radius. Camera zoom and focus
+// actions, mission repeats, headings without a WPML equivalent and intervals
+// on the last waypoint are dropped, each with a WarningRuleGSProUnmapped
+// warning so operators know what to add back.
+func ImportGSPro(data []byte) (*Waylines, []Warning, error) {
+	var mission gsProMission
+	if err := json.Unmarshal(data, &mission); err != nil {
+		return nil, nil, fmt.Errorf(ErrParseGSPro, err)
+	}
+	im := &gsProImport{mission: mission}
+	waylines := im.convert()
+	if err := waylines.Validate(); err != nil {
+		return nil, im.warnings, fmt.Errorf(ErrInvalidGSProMission, err)
+	}
+	return waylines, im.warnings, nil
+}
+
+type gsProImport struct {
+	mission  gsProMission
+	warnings []Warning
+}
+
+func (im *gsProImport) warn(waypoint int, format string, args ...any) {
+	warning := Warning{Rule: WarningRuleGSProUnmapped, Message: fmt.Sprintf(format, args...)}
+	if waypoint >= 0 {
+		warning.WaypointIndices = []int{waypoint}
+	}
+	im.warnings = append(im.warnings, warning)
+}
+
+func (im *gsProImport) convert() *Waylines {
+	mission := im.mission
+	name := mission.Name
+	if name == "" {
+		name = "GS Pro Mission"
+	}
+
+	waylines := &Waylines{
+		Name:                    name,
+		Description:             "Imported from DJI GS Pro",
+		DroneModel:              DroneM3Series,
+		PayloadModel:            PayloadMavic3ECamera,
+		TemplateType:            TemplateTypeWaypoint,
+		GlobalSpeed:             mission.AutoFlightSpeed,
+		PhotoSettings:           []string{"wide"},
+		FinishAction:            im.finishAction(),
+		ExitOnRCLost:            RCLostActionGoContinue,
+		HeightType:              HeightModeRelativeToStartPoint,
+		ClimbMode:               "vertical",
+		AircraftYawMode:         HeadingModeFollowWayline,
+		GimbalPitchMode:         "usePointSetting",
+		GlobalTransitionalSpeed: mission.AutoFlightSpeed,
+	}
+	if mission.ExitMissionOnRCSignalLost {
+		waylines.ExitOnRCLost = RCLostActionExecuteLostAction
+		waylines.ExecuteRCLostAction = ExecuteRCLostActionGoBack
+	}
+	if mission.RepeatTimes > 1 {
+		im.warn(-1, "the mission repeats %d times in GS Pro; WPML flies it once", mission.RepeatTimes)
+	}
+
+	waypointHeading := false
+	switch mission.HeadingMode {
+	case "", "auto":
+	case "controlByRemoteController":
+		waylines.AircraftYawMode = "manual"
+	case "usingWaypointHeading":
+		waylines.AircraftYawMode = "free"
+		waypointHeading = true
+	case "towardPointOfInterest":
+		if poi := mission.PointOfInterest; poi != nil {
+			waylines.AircraftYawMode = HeadingModeTowardPOI
+			waylines.HeadingPOI = &LatLng{Latitude: poi.Latitude, Longitude: poi.Longitude}
+		} else {
+			im.warn(-1, "heading mode %s has no point of interest; the aircraft follows the wayline", mission.HeadingMode)
+		}
+	default:
+		im.warn(-1, "heading mode %s has no WPML equivalent; the aircraft follows the wayline", mission.HeadingMode)
+	}
+
+	waylines.Waypoints = make([]WaylinesWaypoint, len(mission.Waypoints))
+	for i, gsWaypoint := range mission.Waypoints {
+		waylines.Waypoints[i] = im.convertWaypoint(i, gsWaypoint, waypointHeading)
+	}
+	if mission.FlightPathMode == "curved" {
+		// The first and last waypoint are not turns.
+		for i := 1; i < len(waylines.Waypoints)-1; i++ {
+			if radius := mission.Waypoints[i].CornerRadiusInMeters; radius > 0 {
+				waylines.Waypoints[i].WaypointTurnMode = TurnModeCoordinateTurn
+				waylines.Waypoints[i].TurnDampingRadius = radius
+			}
+		}
+	}
+
+	if len(waylines.Waypoints) > 0 {
+		waylines.GlobalHeight = waylines.Waypoints[0].Height
+		waylines.SafeHeight = math.Min(math.Max(20, waylines.GlobalHeight), 200)
+		waylines.GlobalRTHHeight = waylines.SafeHeight
+	}
+	return waylines
+}
+
+func (im *gsProImport) finishAction() FinishAction {
+	finishedAction := im.mission.FinishedAction
+	if finishedAction == "" {
+		return FinishActionGoHome
+	}
+	if action, ok := gsProFinishActions[finishedAction]; ok {
+		return action
+	}
+	im.warn(-1, "finish action %s has no WPML equivalent; the aircraft returns home", finishedAction)
+	return FinishActionGoHome
+}
+
+func (im *gsProImport) convertWaypoint(i int, gsWaypoint gsProWaypoint, waypointHeading bool) WaylinesWaypoint {
+	wp := WaylinesWaypoint{
+		Latitude:    gsWaypoint.Coordinate.Latitude,
+		Longitude:   gsWaypoint.Coordinate.Longitude,
+		Height:      gsWaypoint.Altitude,
+		Speed:       gsWaypoint.Speed,
+		TriggerType: TriggerTypeReachPoint,
+	}
+
+	var pathMode *string
+	switch gsWaypoint.TurnMode {
+	case HeadingPathModeClockwise, HeadingPathModeCounterClockwise:
+		pathMode = stringPtr(gsWaypoint.TurnMode)
+	}
+	if waypointHeading {
+		wp.Actions = append(wp.Actions, gsProRotateYaw(gsWaypoint.Heading, pathMode))
+	}
+	if im.mission.RotateGimbalPitch {
+		wp.Actions = append(wp.Actions, gsProGimbalPitch(gsWaypoint.GimbalPitch))
+	}
+
+	for _, action := range gsWaypoint.Actions {
+		switch action.ActionType {
+		case "stay":
+			if action.ActionParam <= 0 {
+				continue
+			}
+			wp.Actions = append(wp.Actions, ActionRequest{
+				Type:   ActionTypeHover,
+				Action: &HoverAction{HoverTime: action.ActionParam / 1000},
+			})
+		case "shootPhoto":
+			wp.Actions = append(wp.Actions, ActionRequest{
+				Type:   ActionTypeTakePhoto,
+				Action: &TakePhotoAction{PayloadPositionIndex: PayloadPosition0},
+			})
+		case "startRecord":
+			wp.Actions = append(wp.Actions, ActionRequest{
+				Type:   ActionTypeStartRecord,
+				Action: &StartRecordAction{PayloadPositionIndex: PayloadPosition0},
+			})
+		case "stopRecord":
+			wp.Actions = append(wp.Actions, ActionRequest{
+				Type:   ActionTypeStopRecord,
+				Action: &StopRecordAction{PayloadPositionIndex: PayloadPosition0},
+			})
+		case "rotateAircraft":
+			wp.Actions = append(wp.Actions, gsProRotateYaw(action.ActionParam, pathMode))
+		case "gimbalPitch":
+			wp.Actions = append(wp.Actions, gsProGimbalPitch(action.ActionParam))
+		default:
+			im.warn(i, "waypoint %d action %s has no WPML equivalent and was dropped", i, action.ActionType)
+		}
+	}
+
+	var capture *IntervalCapture
+	switch {
+	case gsWaypoint.ShootPhotoTimeInterval > 0:
+		capture = &IntervalCapture{TriggerType: TriggerTypeMultipleTiming, Interval: gsWaypoint.ShootPhotoTimeInterval}
+	case gsWaypoint.ShootPhotoDistanceInterval > 0:
+		capture = &IntervalCapture{TriggerType: TriggerTypeMultipleDistance, Interval: gsWaypoint.ShootPhotoDistanceInterval}
+	}
+	if capture != nil && i == len(im.mission.Waypoints)-1 {
+		im.warn(i, "waypoint %d photo interval has no following leg and was dropped", i)
+		capture = nil
+	}
+	wp.IntervalCapture = capture
+	return wp
+}
+
+func gsProRotateYaw(heading float64, pathMode *string) ActionRequest {
+	return ActionRequest{
+		Type: ActionTypeRotateYaw,
+		Action: &RotateYawAction{
+			AircraftHeading:  normalizeAngle(heading),
+			AircraftPathMode: pathMode,
+		},
+	}
+}
+
+func gsProGimbalPitch(pitch float64) ActionRequest {
+	return ActionRequest{
+		Type: ActionTypeGimbalRotate,
+		Action: &GimbalRotateAction{
+			PayloadPositionIndex:    PayloadPosition0,
+			GimbalHeadingYawBase:    GimbalHeadingYawBaseAircraft,
+			GimbalRotateMode:        GimbalRotateModeAbsoluteAngle,
+			GimbalPitchRotateEnable: true,
+			GimbalPitchRotateAngle:  pitch,
+		},
+	}
+}
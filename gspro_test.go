@@ -0,0 +1,191 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gsProExport = `{
+	"name": "Tower Inspection",
+	"autoFlightSpeed": 6,
+	"finishedAction": "autoLand",
+	"headingMode": "usingWaypointHeading",
+	"flightPathMode": "curved",
+	"rotateGimbalPitch": true,
+	"exitMissionOnRCSignalLost": true,
+	"repeatTimes": 2,
+	"waypoints": [
+		{
+			"coordinate": {"latitude": 39.900, "longitude": 116.400},
+			"altitude": 40,
+			"heading": 0,
+			"gimbalPitch": -30,
+			"waypointActions": [
+				{"actionType": "stay", "actionParam": 2000},
+				{"actionType": "shootPhoto", "actionParam": 0},
+				{"actionType": "cameraZoom", "actionParam": 4}
+			]
+		},
+		{
+			"coordinate": {"latitude": 39.901, "longitude": 116.400},
+			"altitude": 50,
+			"heading": 270,
+			"turnMode": "counterClockwise",
+			"cornerRadiusInMeters": 10,
+			"gimbalPitch": -90,
+			"shootPhotoDistanceInterval": 20
+		},
+		{
+			"coordinate": {"latitude": 39.901, "longitude": 116.401},
+			"altitude": 50,
+			"speed": 4,
+			"heading": 90,
+			"gimbalPitch": -90,
+			"waypointActions": [
+				{"actionType": "rotateAircraft", "actionParam": 180},
+				{"actionType": "cameraFocus", "actionParam": 0}
+			]
+		}
+	]
+}`
+
+func TestImportGSPro(t *testing.T) {
+	waylines, warnings, err := ImportGSPro([]byte(gsProExport))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Tower Inspection", waylines.Name)
+	assert.Equal(t, 6.0, waylines.GlobalSpeed)
+	assert.Equal(t, FinishActionAutoLand, waylines.FinishAction)
+	assert.Equal(t, RCLostActionExecuteLostAction, waylines.ExitOnRCLost)
+	assert.Equal(t, HeightModeRelativeToStartPoint, waylines.HeightType)
+	require.Len(t, waylines.Waypoints, 3)
+
+	first := waylines.Waypoints[0]
+	assert.Equal(t, 40.0, first.Height)
+	actionTypes := make([]string, len(first.Actions))
+	for i, action := range first.Actions {
+		actionTypes[i] = action.Type
+	}
+	assert.Equal(t, []string{ActionTypeRotateYaw, ActionTypeGimbalRotate, ActionTypeHover, ActionTypeTakePhoto}, actionTypes)
+	assert.Equal(t, 2.0, first.Actions[2].Action.(*HoverAction).HoverTime)
+
+	second := waylines.Waypoints[1]
+	assert.Equal(t, TriggerTypeReachPoint, second.TriggerType)
+	assert.Equal(t, &IntervalCapture{TriggerType: TriggerTypeMultipleDistance, Interval: 20}, second.IntervalCapture)
+	assert.Equal(t, TurnModeCoordinateTurn, second.WaypointTurnMode)
+	assert.Equal(t, 10.0, second.TurnDampingRadius)
+	heading := second.Actions[0].Action.(*RotateYawAction)
+	assert.Equal(t, -90.0, heading.AircraftHeading)
+	assert.Equal(t, HeadingPathModeCounterClockwise, *heading.AircraftPathMode)
+
+	last := waylines.Waypoints[2]
+	assert.Equal(t, 4.0, last.Speed)
+	assert.Empty(t, last.WaypointTurnMode, "the last waypoint is not a turn")
+
+	require.Len(t, warnings, 3)
+	for _, warning := range warnings {
+		assert.Equal(t, WarningRuleGSProUnmapped, warning.Rule)
+	}
+	assert.Contains(t, warnings[0].Message, "repeats 2 times")
+	assert.Equal(t, []int{0}, warnings[1].WaypointIndices)
+	assert.Contains(t, warnings[1].Message, "cameraZoom")
+	assert.Contains(t, warnings[2].Message, "waypoint 2 action cameraFocus")
+}
+
+func TestWaylinesFromGSPro(t *testing.T) {
+	waylines, err := WaylinesFromGSPro([]byte(gsProExport))
+	require.NoError(t, err)
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	placemarks := mission.Waylines.Document.Folders[0].Placemarks
+	require.Len(t, placemarks, 3)
+	assert.Len(t, placemarks[1].ActionGroups, 2)
+}
+
+func TestImportGSPro_IntervalWithGimbalPitch(t *testing.T) {
+	mission := `{
+		"autoFlightSpeed": 5,
+		"rotateGimbalPitch": true,
+		"waypoints": [
+			{"coordinate": {"latitude": 39.900, "longitude": 116.400}, "altitude": 40, "gimbalPitch": -45, "shootPhotoTimeInterval": 2},
+			{"coordinate": {"latitude": 39.901, "longitude": 116.400}, "altitude": 40, "gimbalPitch": -45, "shootPhotoTimeInterval": 2}
+		]
+	}`
+	waylines, warnings, err := ImportGSPro([]byte(mission))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, []int{1}, warnings[0].WaypointIndices)
+	assert.Contains(t, warnings[0].Message, "photo interval")
+	assert.Nil(t, waylines.Waypoints[1].IntervalCapture)
+
+	wpmlMission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	groups := wpmlMission.Waylines.Document.Folders[0].Placemarks[0].ActionGroups
+	require.Len(t, groups, 2)
+
+	pitch := groups[0]
+	assert.Equal(t, TriggerTypeReachPoint, pitch.ActionTrigger.ActionTriggerType)
+	require.Len(t, pitch.Actions, 1)
+	assert.Equal(t, ActionTypeGimbalRotate, pitch.Actions[0].ActionActuatorFunc)
+
+	interval := groups[1]
+	assert.Equal(t, TriggerTypeMultipleTiming, interval.ActionTrigger.ActionTriggerType)
+	assert.Equal(t, 2.0, interval.ActionTrigger.param())
+	assert.Equal(t, 0, interval.ActionGroupStartIndex)
+	assert.Equal(t, 1, interval.ActionGroupEndIndex)
+	require.Len(t, interval.Actions, 1)
+	assert.Equal(t, ActionTypeTakePhoto, interval.Actions[0].ActionActuatorFunc)
+	assert.NotEqual(t, pitch.ActionGroupID, interval.ActionGroupID)
+}
+
+func TestImportGSPro_HeadingModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mission  string
+		yawMode  string
+		warnings int
+	}{
+		{
+			name:    "Auto",
+			mission: `{"autoFlightSpeed": 5, "headingMode": "auto"}`,
+			yawMode: HeadingModeFollowWayline,
+		},
+		{
+			name:    "Point of interest",
+			mission: `{"autoFlightSpeed": 5, "headingMode": "towardPointOfInterest", "pointOfInterest": {"latitude": 39.9005, "longitude": 116.4005}}`,
+			yawMode: HeadingModeTowardPOI,
+		},
+		{
+			name:     "Initial direction",
+			mission:  `{"autoFlightSpeed": 5, "headingMode": "usingInitialDirection"}`,
+			yawMode:  HeadingModeFollowWayline,
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.mission[:len(tt.mission)-1] + `, "waypoints": [
+				{"coordinate": {"latitude": 39.900, "longitude": 116.400}, "altitude": 40},
+				{"coordinate": {"latitude": 39.901, "longitude": 116.400}, "altitude": 40}
+			]}`
+			waylines, warnings, err := ImportGSPro([]byte(data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.yawMode, waylines.AircraftYawMode)
+			assert.Len(t, warnings, tt.warnings)
+		})
+	}
+}
+
+func TestImportGSPro_Errors(t *testing.T) {
+	_, err := WaylinesFromGSPro([]byte(`{"waypoints": [`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse GS Pro mission")
+
+	_, err = WaylinesFromGSPro([]byte(`{"autoFlightSpeed": 5, "waypoints": []}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not convert to a valid mission")
+}
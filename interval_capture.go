@@ -0,0 +1,117 @@
+package wpml
+
+import "fmt"
+
+// IntervalCapture takes a photo every Interval seconds (multipleTiming) or
+// meters (multipleDistance) on the leg from its waypoint to the next one. It
+// is emitted as an action group of its own after the group of the waypoint's
+// Actions, which keep their trigger and still run once on arrival.
+type IntervalCapture struct {
+	TriggerType string  `json:"trigger_type" validate:"oneof=multipleTiming multipleDistance"`
+	Interval    float64 `json:"interval" validate:"gt=0"`
+}
+
+func (c IntervalCapture) trigger() ActionTrigger {
+	return ActionTrigger{ActionTriggerType: c.TriggerType, ActionTriggerParam: float64Ptr(c.Interval)}
+}
+
+// UnstoppedIntervalCapture returns the index of the waypoint that starts the
+// last time-lapse capture if no stopTimeLapse action follows it, or -1 when
+// every capture is stopped. Interval captures under a multipleTiming or
+// multipleDistance trigger, or an IntervalCapture, end at the next waypoint;
+// on the last waypoint there is none, so its index is reported too.
+func (w *Waylines) UnstoppedIntervalCapture() int {
+	if start := w.unstoppedTimeLapse(); start >= 0 {
+		return start
+	}
+	if w.lastWaypointIntervalCapture() {
+		return len(w.Waypoints) - 1
+	}
+	return -1
+}
+
+func (w *Waylines) unstoppedTimeLapse() int {
+	start := -1
+	for i, wp := range w.Waypoints {
+		for _, action := range wp.Actions {
+			switch action.Type {
+			case ActionTypeStopTimeLapse:
+				start = -1
+			case ActionTypeStartTimeLapse:
+				start = i
+			}
+		}
+	}
+	return start
+}
+
+// lastWaypointIntervalCapture reports whether the last waypoint starts an
+// interval capture, which has no following waypoint to end its group.
+func (w *Waylines) lastWaypointIntervalCapture() bool {
+	if len(w.Waypoints) == 0 {
+		return false
+	}
+	last := w.Waypoints[len(w.Waypoints)-1]
+	if last.IntervalCapture != nil {
+		return true
+	}
+	return len(last.Actions) > 0 && isIntervalTrigger(last.actionTrigger().ActionTriggerType)
+}
+
+// StopIntervalCapture stops captures that would otherwise run into the finish
+// action. On the last waypoint it drops an IntervalCapture and switches
+// actions under an interval trigger to reachPoint, so they run once on
+// arrival; then it appends a stopTimeLapse action to the last waypoint for an
+// unstopped time-lapse capture. It reports whether the mission was changed.
+// No stop is added when the last waypoint's actions use another trigger than
+// reachPoint, since the stop would then not run once on arrival; the capture
+// stays reported by Warnings.
+func (w *Waylines) StopIntervalCapture() bool {
+	if w.UnstoppedIntervalCapture() < 0 {
+		return false
+	}
+
+	last := &w.Waypoints[len(w.Waypoints)-1]
+	changed := false
+	if w.lastWaypointIntervalCapture() {
+		last.IntervalCapture = nil
+		if isIntervalTrigger(last.actionTrigger().ActionTriggerType) {
+			if last.ActionTrigger != nil {
+				last.ActionTrigger = &ActionTrigger{ActionTriggerType: TriggerTypeReachPoint}
+			} else {
+				last.TriggerType = TriggerTypeReachPoint
+				last.TriggerParam = 0
+			}
+		}
+		changed = true
+	}
+
+	if w.unstoppedTimeLapse() < 0 || last.actionTrigger().ActionTriggerType != TriggerTypeReachPoint {
+		return changed
+	}
+	last.Actions = append(last.Actions, ActionRequest{
+		Type:   ActionTypeStopTimeLapse,
+		Action: &StopTimeLapseAction{PayloadPositionIndex: w.PayloadPositionIndex},
+	})
+	return true
+}
+
+func intervalCaptureWarnings(w *Waylines) []Warning {
+	var warnings []Warning
+	if start := w.unstoppedTimeLapse(); start >= 0 {
+		warnings = append(warnings, Warning{
+			Rule:            WarningRuleUnstoppedIntervalCapture,
+			WaypointIndices: []int{start},
+			Message:         fmt.Sprintf(ErrIntervalCaptureNotStopped, start),
+		})
+	}
+	if w.lastWaypointIntervalCapture() {
+		last := len(w.Waypoints) - 1
+		warnings = append(warnings, Warning{
+			Rule:            WarningRuleUnstoppedIntervalCapture,
+			WaypointIndices: []int{last},
+			Message:         fmt.Sprintf(ErrIntervalCaptureAtLastWaypoint, last),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,170 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnstoppedIntervalCapture(t *testing.T) {
+	startTimeLapse := ActionRequest{Type: ActionTypeStartTimeLapse, Action: &StartTimeLapseAction{}}
+	stopTimeLapse := ActionRequest{Type: ActionTypeStopTimeLapse, Action: &StopTimeLapseAction{}}
+
+	tests := []struct {
+		name     string
+		setup    func(w *Waylines)
+		expected int
+	}{
+		{
+			name:     "no interval capture",
+			setup:    func(w *Waylines) { w.Waypoints[0].Actions = []ActionRequest{photoAction()} },
+			expected: -1,
+		},
+		{
+			name: "interval trigger ends with its leg",
+			setup: func(w *Waylines) {
+				w.Waypoints[1].TriggerType = TriggerTypeMultipleDistance
+				w.Waypoints[1].TriggerParam = 10
+				w.Waypoints[1].Actions = []ActionRequest{photoAction()}
+			},
+			expected: -1,
+		},
+		{
+			name: "interval trigger on the last waypoint",
+			setup: func(w *Waylines) {
+				w.Waypoints[2].TriggerType = TriggerTypeMultipleDistance
+				w.Waypoints[2].TriggerParam = 10
+				w.Waypoints[2].Actions = []ActionRequest{photoAction()}
+			},
+			expected: 2,
+		},
+		{
+			name: "interval capture on the last waypoint",
+			setup: func(w *Waylines) {
+				w.Waypoints[2].IntervalCapture = &IntervalCapture{TriggerType: TriggerTypeMultipleTiming, Interval: 2}
+			},
+			expected: 2,
+		},
+		{
+			name:     "time lapse never stopped",
+			setup:    func(w *Waylines) { w.Waypoints[1].Actions = []ActionRequest{startTimeLapse} },
+			expected: 1,
+		},
+		{
+			name: "time lapse stopped later",
+			setup: func(w *Waylines) {
+				w.Waypoints[0].Actions = []ActionRequest{startTimeLapse}
+				w.Waypoints[2].Actions = []ActionRequest{stopTimeLapse}
+			},
+			expected: -1,
+		},
+		{
+			name: "restarted after stop",
+			setup: func(w *Waylines) {
+				w.Waypoints[0].Actions = []ActionRequest{startTimeLapse}
+				w.Waypoints[1].Actions = []ActionRequest{stopTimeLapse, startTimeLapse}
+			},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Interval", 3)
+			tt.setup(waylines)
+
+			assert.Equal(t, tt.expected, waylines.UnstoppedIntervalCapture())
+		})
+	}
+}
+
+func TestStopIntervalCapture(t *testing.T) {
+	waylines := waylinesWithActionsAt("Interval", 3)
+	waylines.Waypoints[0].Actions = []ActionRequest{{Type: ActionTypeStartTimeLapse, Action: &StartTimeLapseAction{}}}
+
+	warnings := waylines.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarningRuleUnstoppedIntervalCapture, warnings[0].Rule)
+	assert.Equal(t, []int{0}, warnings[0].WaypointIndices)
+
+	assert.True(t, waylines.StopIntervalCapture())
+	last := waylines.Waypoints[2]
+	require.Len(t, last.Actions, 1)
+	assert.Equal(t, ActionTypeStopTimeLapse, last.Actions[0].Type)
+	assert.Empty(t, waylines.Warnings())
+	assert.NoError(t, waylines.Validate())
+
+	assert.False(t, waylines.StopIntervalCapture(), "already stopped")
+}
+
+func TestStopIntervalCapture_LastWaypointInterval(t *testing.T) {
+	waylines := waylinesWithActionsAt("Interval", 2)
+	waylines.Waypoints[0].Actions = []ActionRequest{{Type: ActionTypeStartTimeLapse, Action: &StartTimeLapseAction{}}}
+	waylines.Waypoints[1].TriggerType = TriggerTypeMultipleTiming
+	waylines.Waypoints[1].TriggerParam = 2
+	waylines.Waypoints[1].Actions = []ActionRequest{photoAction()}
+	waylines.Waypoints[1].IntervalCapture = &IntervalCapture{TriggerType: TriggerTypeMultipleDistance, Interval: 10}
+	assert.Len(t, intervalCaptureWarnings(waylines), 2)
+
+	assert.True(t, waylines.StopIntervalCapture())
+	last := waylines.Waypoints[1]
+	assert.Equal(t, TriggerTypeReachPoint, last.actionTrigger().ActionTriggerType)
+	assert.Nil(t, last.IntervalCapture)
+	require.Len(t, last.Actions, 2)
+	assert.Equal(t, ActionTypeStopTimeLapse, last.Actions[1].Type)
+	assert.Equal(t, -1, waylines.UnstoppedIntervalCapture())
+	assert.Empty(t, waylines.Warnings())
+	assert.NoError(t, waylines.Validate())
+}
+
+func TestStopIntervalCapture_LastWaypointActionTrigger(t *testing.T) {
+	waylines := waylinesWithActionsAt("Interval", 2)
+	waylines.Waypoints[1].ActionTrigger = &ActionTrigger{ActionTriggerType: TriggerTypeMultipleDistance, ActionTriggerParam: float64Ptr(5)}
+	waylines.Waypoints[1].Actions = []ActionRequest{photoAction()}
+
+	assert.True(t, waylines.StopIntervalCapture())
+	assert.Equal(t, TriggerTypeReachPoint, waylines.Waypoints[1].actionTrigger().ActionTriggerType)
+	assert.Len(t, waylines.Waypoints[1].Actions, 1)
+	assert.False(t, waylines.StopIntervalCapture(), "already stopped")
+}
+
+func TestConvert_IntervalGroupEndsAtNextWaypoint(t *testing.T) {
+	waylines := waylinesWithActionsAt("Interval", 3)
+	waylines.Waypoints[0].TriggerType = TriggerTypeMultipleDistance
+	waylines.Waypoints[0].TriggerParam = 10
+	waylines.Waypoints[0].Actions = []ActionRequest{photoAction()}
+	waylines.Waypoints[2].Actions = []ActionRequest{photoAction()}
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	placemarks := mission.Waylines.Document.Folders[0].Placemarks
+	interval := placemarks[0].ActionGroups[0]
+	assert.Equal(t, 0, interval.ActionGroupStartIndex)
+	assert.Equal(t, 1, interval.ActionGroupEndIndex)
+	reachPoint := placemarks[2].ActionGroups[0]
+	assert.Equal(t, 2, reachPoint.ActionGroupStartIndex)
+	assert.Equal(t, 2, reachPoint.ActionGroupEndIndex)
+	assert.Equal(t, 1, mission.Template.Document.Folders[0].Placemarks[0].ActionGroups[0].ActionGroupEndIndex)
+}
+
+func TestIntervalCapture(t *testing.T) {
+	waylines := waylinesWithActionsAt("Interval", 2, 0)
+	waylines.Waypoints[0].IntervalCapture = &IntervalCapture{TriggerType: TriggerTypeMultipleDistance, Interval: 50}
+	require.NoError(t, waylines.Validate())
+
+	assert.Equal(t, 2, waylines.ActionGroupCount())
+	// One shot on arrival plus three from the interval over the 111 m leg.
+	assert.Equal(t, 4, waylines.PhotoCount())
+	assert.Empty(t, waylines.TriggerActionMismatches())
+
+	waylines.Waypoints[1].IntervalCapture = &IntervalCapture{TriggerType: TriggerTypeMultipleTiming, Interval: 2}
+	mismatches := waylines.TriggerActionMismatches()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, 1, mismatches[0].WaypointIndex)
+	assert.Equal(t, TriggerTypeMultipleTiming, mismatches[0].TriggerType)
+
+	waylines.Waypoints[1].IntervalCapture = &IntervalCapture{TriggerType: TriggerTypeReachPoint, Interval: 0}
+	assert.Error(t, waylines.Validate())
+}
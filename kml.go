@@ -0,0 +1,123 @@
+package wpml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KMLOptions controls the plain KML export.
+type KMLOptions struct {
+	// ExtendedData attaches each waypoint's speed, height, turn mode and
+	// actions to its placemark, where Google Earth shows them in the info
+	// balloon. It noticeably enlarges the file for long missions.
+	ExtendedData bool
+}
+
+type kmlRoot struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name         string           `xml:"name"`
+	ExtendedData *kmlExtendedData `xml:"ExtendedData,omitempty"`
+	Point        *kmlGeometry     `xml:"Point,omitempty"`
+	LineString   *kmlGeometry     `xml:"LineString,omitempty"`
+}
+
+type kmlGeometry struct {
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+type kmlExtendedData struct {
+	Data []kmlData `xml:"Data"`
+}
+
+type kmlData struct {
+	Name        string `xml:"name,attr"`
+	DisplayName string `xml:"displayName"`
+	Value       string `xml:"value"`
+}
+
+// WriteKML writes the route as plain KML for previewing in Google Earth and
+// other KML viewers: one placemark per waypoint and a line along the route.
+// EGM96 heights are written as absolute altitudes, as are heights relative to
+// the start point when the takeoff reference point gives its height. Other
+// heights are shown relative to the ground, which for heights relative to the
+// start point is only exact where the ground is as high as the takeoff point.
+// The mission is not validated, so drafts can be previewed; use WriteKmz for
+// files meant for the aircraft.
+func (w *Waylines) WriteKML(out io.Writer, opts KMLOptions) error {
+	root := kmlRoot{
+		Xmlns:    "http://www.opengis.net/kml/2.2",
+		Document: kmlDocument{Name: w.Name},
+	}
+
+	altitudeMode, offset := w.kmlAltitudeMode()
+	coordinates := make([]string, len(w.Waypoints))
+	for i, wp := range w.Waypoints {
+		coordinates[i] = fmt.Sprintf("%g,%g,%g", wp.Longitude, wp.Latitude, w.waypointHeight(wp)+offset)
+		placemark := kmlPlacemark{
+			Name:  fmt.Sprintf("Waypoint %d", i),
+			Point: &kmlGeometry{AltitudeMode: altitudeMode, Coordinates: coordinates[i]},
+		}
+		if opts.ExtendedData {
+			placemark.ExtendedData = w.kmlExtendedData(i)
+		}
+		root.Document.Placemarks = append(root.Document.Placemarks, placemark)
+	}
+	if len(coordinates) > 1 {
+		root.Document.Placemarks = append(root.Document.Placemarks, kmlPlacemark{
+			Name:       "Route",
+			LineString: &kmlGeometry{AltitudeMode: altitudeMode, Coordinates: strings.Join(coordinates, " ")},
+		})
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return fmt.Errorf(ErrMarshalKML, err)
+	}
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// kmlAltitudeMode returns the KML altitude mode of the exported heights and
+// the offset that turns mission heights into it.
+func (w *Waylines) kmlAltitudeMode() (mode string, offset float64) {
+	switch {
+	case w.heightMode() == HeightModeEGM96:
+		return "absolute", 0
+	case w.heightMode() == HeightModeRelativeToStartPoint && w.hasTakeOffRefPoint():
+		return "absolute", w.TakeOffRefPointHeight
+	default:
+		return "relativeToGround", 0
+	}
+}
+
+func (w *Waylines) kmlExtendedData(i int) *kmlExtendedData {
+	wp := w.Waypoints[i]
+	var actions []string
+	for _, group := range w.actionGroups(i) {
+		for _, action := range group.actions {
+			actions = append(actions, action.Type)
+		}
+	}
+	return &kmlExtendedData{Data: []kmlData{
+		{Name: "speed", DisplayName: "Speed (m/s)", Value: fmt.Sprintf("%g", w.waypointSpeed(wp))},
+		{Name: "height", DisplayName: "Height (m)", Value: fmt.Sprintf("%g", w.waypointHeight(wp))},
+		{Name: "turnMode", DisplayName: "Turn mode", Value: w.turnModeAt(i)},
+		{Name: "actions", DisplayName: "Actions", Value: strings.Join(actions, ", ")},
+	}}
+}
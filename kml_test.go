@@ -0,0 +1,112 @@
+package wpml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteKML(t *testing.T) {
+	waylines := waylinesWithActionsAt("Survey <North> & East", 3, 1)
+	waylines.Waypoints[2].Speed = 8
+
+	t.Run("Plain export", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, waylines.WriteKML(&buf, KMLOptions{}))
+		assert.NotContains(t, buf.String(), "ExtendedData")
+		assert.Contains(t, buf.String(), "Survey &lt;North&gt; &amp; East")
+
+		var root kmlRoot
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &root))
+		assert.Equal(t, waylines.Name, root.Document.Name)
+		require.Len(t, root.Document.Placemarks, 4)
+		assert.Equal(t, "116.3974,39.9093,50", root.Document.Placemarks[0].Point.Coordinates)
+		assert.NotNil(t, root.Document.Placemarks[3].LineString)
+	})
+
+	t.Run("Extended data", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, waylines.WriteKML(&buf, KMLOptions{ExtendedData: true}))
+
+		var root kmlRoot
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &root))
+		values := func(i int) map[string]string {
+			result := map[string]string{}
+			for _, data := range root.Document.Placemarks[i].ExtendedData.Data {
+				result[data.Name] = data.Value
+			}
+			return result
+		}
+
+		assert.Equal(t, map[string]string{
+			"speed":    "15",
+			"height":   "50",
+			"turnMode": TurnModeToPointAndStopWithContinuityCurvature,
+			"actions":  ActionTypeTakePhoto,
+		}, values(1))
+		assert.Equal(t, "8", values(2)["speed"])
+		assert.Empty(t, values(0)["actions"])
+		assert.Nil(t, root.Document.Placemarks[3].ExtendedData)
+	})
+}
+
+func TestWriteKML_AltitudeMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(w *Waylines)
+		mode        string
+		coordinates string
+	}{
+		{
+			name:        "relative without takeoff reference",
+			setup:       func(w *Waylines) {},
+			mode:        "relativeToGround",
+			coordinates: "116.3974,39.9093,50",
+		},
+		{
+			name: "relative with takeoff reference",
+			setup: func(w *Waylines) {
+				w.TakeOffRefPointLatitude = 39.9
+				w.TakeOffRefPointLongitude = 116.4
+				w.TakeOffRefPointHeight = 45
+			},
+			mode:        "absolute",
+			coordinates: "116.3974,39.9093,95",
+		},
+		{
+			name: "EGM96",
+			setup: func(w *Waylines) {
+				w.HeightType = HeightModeEGM96
+				w.Waypoints[0].Height = 120
+			},
+			mode:        "absolute",
+			coordinates: "116.3974,39.9093,120",
+		},
+		{
+			name:        "above ground level",
+			setup:       func(w *Waylines) { w.HeightType = HeightModeAboveGroundLevel },
+			mode:        "relativeToGround",
+			coordinates: "116.3974,39.9093,50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Altitude", 2)
+			tt.setup(waylines)
+
+			var buf bytes.Buffer
+			require.NoError(t, waylines.WriteKML(&buf, KMLOptions{}))
+			var root kmlRoot
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &root))
+
+			point := root.Document.Placemarks[0].Point
+			assert.Equal(t, tt.mode, point.AltitudeMode)
+			assert.Equal(t, tt.coordinates, point.Coordinates)
+			assert.Equal(t, tt.mode, root.Document.Placemarks[2].LineString.AltitudeMode)
+		})
+	}
+}
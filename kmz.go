@@ -1,8 +1,10 @@
 package wpml
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,7 +33,12 @@ func CreateKmz(mission *WPMLMission, kmzPath string) error {
 	return nil
 }
 
-func CreateKmzBuffer(mission *WPMLMission) (*bytes.Buffer, error) {
+type wpmzEntry struct {
+	name string
+	data []byte
+}
+
+func renderWPMZEntries(mission *WPMLMission) ([]wpmzEntry, error) {
 	if mission == nil {
 		return nil, ErrMissionCannotBeEmpty
 	}
@@ -49,38 +56,150 @@ func CreateKmzBuffer(mission *WPMLMission) (*bytes.Buffer, error) {
 	if err != nil {
 		return nil, fmt.Errorf(ErrSerializeWaylines, err)
 	}
+	if mission.HeaderComment != "" {
+		if err := (RenderOptions{HeaderComment: mission.HeaderComment}).Validate(); err != nil {
+			return nil, err
+		}
+		templateData = insertHeaderComment(templateData, mission.HeaderComment)
+		waylinesData = insertHeaderComment(waylinesData, mission.HeaderComment)
+	}
+
+	return []wpmzEntry{
+		{name: "wpmz/template.kml", data: templateData},
+		{name: "wpmz/waylines.wpml", data: waylinesData},
+	}, nil
+}
+
+func CreateKmzBuffer(mission *WPMLMission) (*bytes.Buffer, error) {
 	buffer := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buffer)
-	templateWriter, err := zipWriter.Create("wpmz/template.kml")
+	if err := WriteKmz(mission, buffer); err != nil {
+		return nil, err
+	}
+	return buffer, nil
+}
+
+// WriteKmz streams the mission as a KMZ archive to out.
+func WriteKmz(mission *WPMLMission, out io.Writer) error {
+	return writeKmz(mission, out, nil)
+}
+
+// WriteKmz converts the mission and streams it as a KMZ archive to out,
+// reporting progress through opts.Progress across both the conversion and the
+// archive entries.
+func (w *Waylines) WriteKmz(out io.Writer, opts RenderOptions) error {
+	progress := newProgressTracker(opts.Progress, renderSteps(w)+kmzEntrySteps)
+
+	mission, err := convertWaylinesToWPMLMission(w, opts, progress)
+	if err != nil {
+		return fmt.Errorf(ErrConvertWaylines, err)
+	}
+
+	return writeKmz(mission, out, progress)
+}
+
+// kmzEntrySteps is the number of progress steps writing the KMZ archive adds,
+// one per entry.
+const kmzEntrySteps = 2
+
+func writeKmz(mission *WPMLMission, out io.Writer, progress *progressTracker) error {
+	entries, err := renderWPMZEntries(mission)
+	if err != nil {
+		return err
+	}
+	modTime := archiveModTime(mission)
+	zipWriter := zip.NewWriter(out)
+	templateWriter, err := zipWriter.CreateHeader(&zip.FileHeader{Name: entries[0].name, Method: zip.Deflate, Modified: modTime})
 	if err != nil {
 		zipWriter.Close()
-		return nil, fmt.Errorf(ErrCreateTemplateEntry, err)
+		return fmt.Errorf(ErrCreateTemplateEntry, err)
 	}
-	if _, err := templateWriter.Write(templateData); err != nil {
+	if _, err := templateWriter.Write(entries[0].data); err != nil {
 		zipWriter.Close()
-		return nil, fmt.Errorf(ErrWriteTemplate, err)
+		return fmt.Errorf(ErrWriteTemplate, err)
 	}
+	progress.step()
 
-	waylinesWriter, err := zipWriter.Create("wpmz/waylines.wpml")
+	waylinesWriter, err := zipWriter.CreateHeader(&zip.FileHeader{Name: entries[1].name, Method: zip.Deflate, Modified: modTime})
 	if err != nil {
 		zipWriter.Close()
-		return nil, fmt.Errorf(ErrCreateWaylinesEntry, err)
+		return fmt.Errorf(ErrCreateWaylinesEntry, err)
 	}
-	if _, err := waylinesWriter.Write(waylinesData); err != nil {
+	if _, err := waylinesWriter.Write(entries[1].data); err != nil {
 		zipWriter.Close()
-		return nil, fmt.Errorf(ErrWriteWaylines, err)
+		return fmt.Errorf(ErrWriteWaylines, err)
 	}
+	progress.step()
 
 	if err := zipWriter.Close(); err != nil {
-		return nil, fmt.Errorf(ErrCloseZIPWriter, err)
+		return fmt.Errorf(ErrCloseZIPWriter, err)
 	}
 
-	return buffer, nil
+	return nil
+}
+
+// WriteWPMZTarGz writes the mission as a gzipped tar with the same wpmz/
+// layout and file contents as the KMZ. It is intended for archival only; DJI
+// Pilot and the aircraft accept the KMZ form.
+func (w *Waylines) WriteWPMZTarGz(out io.Writer) error {
+	mission, err := ConvertWaylinesToWPMLMission(w)
+	if err != nil {
+		return fmt.Errorf(ErrConvertWaylines, err)
+	}
+
+	return WriteWPMZTarGz(mission, out)
+}
+
+func WriteWPMZTarGz(mission *WPMLMission, out io.Writer) error {
+	entries, err := renderWPMZEntries(mission)
+	if err != nil {
+		return err
+	}
+
+	gzipWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzipWriter)
+	modTime := archiveModTime(mission)
+
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     "wpmz/",
+		Mode:     0755,
+		ModTime:  modTime,
+	}); err != nil {
+		return fmt.Errorf(ErrWriteTarHeader, "wpmz/", err)
+	}
+
+	for _, entry := range entries {
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     entry.name,
+			Mode:     0644,
+			Size:     int64(len(entry.data)),
+			ModTime:  modTime,
+		}); err != nil {
+			return fmt.Errorf(ErrWriteTarHeader, entry.name, err)
+		}
+		if _, err := tarWriter.Write(entry.data); err != nil {
+			return fmt.Errorf(ErrWriteTarEntry, entry.name, err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf(ErrCloseTarWriter, err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf(ErrCloseGzipWriter, err)
+	}
+
+	return nil
 }
 
 func CreateKmzBufferFromWaylines(waylines *Waylines) (*bytes.Buffer, error) {
+	return CreateKmzBufferFromWaylinesWithOptions(waylines, RenderOptions{})
+}
 
-	mission, err := ConvertWaylinesToWPMLMission(waylines)
+func CreateKmzBufferFromWaylinesWithOptions(waylines *Waylines, opts RenderOptions) (*bytes.Buffer, error) {
+
+	mission, err := ConvertWaylinesToWPMLMissionWithOptions(waylines, opts)
 	if err != nil {
 		return nil, fmt.Errorf(ErrConvertWaylines, err)
 	}
@@ -88,6 +207,29 @@ func CreateKmzBufferFromWaylines(waylines *Waylines) (*bytes.Buffer, error) {
 	return CreateKmzBuffer(mission)
 }
 
+// archiveModTime is the modification time of the archive entries and the
+// created_at of GenerateKMZJSON: the mission's update time, so that rendering
+// with a fixed RenderOptions.Clock produces identical output, or the current
+// time for missions without one.
+func archiveModTime(mission *WPMLMission) time.Time {
+	if updateTime := mission.Template.Document.UpdateTime; updateTime != 0 {
+		return time.UnixMilli(updateTime).UTC()
+	}
+	return time.Now().UTC()
+}
+
+// insertHeaderComment places the comment on the line after the XML
+// declaration written by MarshalTemplate and MarshalWaylines.
+func insertHeaderComment(data []byte, comment string) []byte {
+	declarationEnd := bytes.IndexByte(data, '\n') + 1
+
+	var buf bytes.Buffer
+	buf.Write(data[:declarationEnd])
+	buf.WriteString("<!-- " + comment + " -->\n")
+	buf.Write(data[declarationEnd:])
+	return buf.Bytes()
+}
+
 func GetKmzInfo(mission *WPMLMission) (map[string]interface{}, error) {
 	buffer, err := CreateKmzBuffer(mission)
 	if err != nil {
@@ -180,9 +322,14 @@ func GenerateKMZJSON(mission *WPMLMission, fileName string) (string, error) {
 		return "", fmt.Errorf("mission不能为空")
 	}
 
+	createdAt := time.Now().UTC()
+	if mission.Template != nil {
+		createdAt = archiveModTime(mission)
+	}
+
 	result := map[string]interface{}{
 		"file_name":  fileName,
-		"created_at": time.Now().Format(time.RFC3339),
+		"created_at": createdAt.Format(time.RFC3339),
 		"template":   nil,
 		"waylines":   nil,
 	}
@@ -1,7 +1,14 @@
 package wpml
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -119,9 +126,203 @@ func TestGenerateKMZJSON(t *testing.T) {
 	assert.Contains(t, jsonData, "waylines")
 }
 
+func TestGenerateKMZJSON_Reproducible(t *testing.T) {
+	renderTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	render := func() string {
+		mission, err := ConvertWaylinesToWPMLMissionWithOptions(createValidWaylines("Test Mission"), RenderOptions{
+			Clock: func() time.Time { return renderTime },
+		})
+		require.NoError(t, err)
+		jsonData, err := GenerateKMZJSON(mission, "test_mission.kmz")
+		require.NoError(t, err)
+		return jsonData
+	}
+
+	jsonData := render()
+	assert.Contains(t, jsonData, `"created_at": "2024-01-02T03:04:05Z"`)
+	assert.Equal(t, jsonData, render())
+}
+
 func TestGenerateKMZJSON_NilMission(t *testing.T) {
 	jsonData, err := GenerateKMZJSON(nil, "test.kmz")
 
 	assert.Error(t, err)
 	assert.Empty(t, jsonData)
 }
+
+func TestWriteWPMZTarGz(t *testing.T) {
+	waylines := createValidWaylines("Archive Mission")
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	require.NoError(t, WriteWPMZTarGz(mission, &archive))
+
+	gzipReader, err := gzip.NewReader(&archive)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+
+	tarFiles := make(map[string][]byte)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeDir {
+			assert.Equal(t, "wpmz/", header.Name)
+			continue
+		}
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		tarFiles[header.Name] = data
+	}
+
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	zipReader, err := zip.NewReader(bytes.NewReader(kmz.Bytes()), int64(kmz.Len()))
+	require.NoError(t, err)
+
+	require.Len(t, tarFiles, len(zipReader.File))
+	for _, file := range zipReader.File {
+		data, err := readZipFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, data, tarFiles[file.Name], file.Name)
+	}
+}
+
+func TestWaylines_WriteWPMZTarGz(t *testing.T) {
+	waylines := createValidWaylines("Archive Mission")
+
+	var archive bytes.Buffer
+	require.NoError(t, waylines.WriteWPMZTarGz(&archive))
+	assert.Greater(t, archive.Len(), 0)
+
+	waylines.Waypoints = nil
+	assert.Error(t, waylines.WriteWPMZTarGz(&archive))
+}
+
+func TestWriteWPMZTarGz_NilMission(t *testing.T) {
+	var archive bytes.Buffer
+	assert.ErrorIs(t, WriteWPMZTarGz(nil, &archive), ErrMissionCannotBeEmpty)
+}
+
+func TestCreateKmzBufferFromWaylinesWithOptions_HeaderComment(t *testing.T) {
+	waylines := createValidWaylines("Traceable Mission")
+	comment := "generator 1.4.2 job=8f3c"
+
+	buffer, err := CreateKmzBufferFromWaylinesWithOptions(waylines, RenderOptions{HeaderComment: comment})
+	require.NoError(t, err)
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+	require.Len(t, zipReader.File, 2)
+
+	for _, file := range zipReader.File {
+		data, err := readZipFile(file)
+		require.NoError(t, err)
+
+		lines := strings.SplitN(string(data), "\n", 3)
+		require.Len(t, lines, 3)
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`, lines[0])
+		assert.Equal(t, "<!-- "+comment+" -->", lines[1], file.Name)
+		assert.NoError(t, ValidateXML(data))
+	}
+
+	mission, err := ParseKMZBuffer(buffer.Bytes())
+	require.NoError(t, err)
+	assert.Len(t, mission.Waylines.Document.Folders[0].Placemarks, 1)
+}
+
+func TestCreateKmzBufferFromWaylinesWithOptions_InvalidHeaderComment(t *testing.T) {
+	waylines := createValidWaylines("Traceable Mission")
+
+	_, err := CreateKmzBufferFromWaylinesWithOptions(waylines, RenderOptions{HeaderComment: "bad --> comment"})
+	assert.ErrorIs(t, err, ErrInvalidHeaderComment)
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	mission.HeaderComment = "also -- bad"
+	_, err = CreateKmzBuffer(mission)
+	assert.ErrorIs(t, err, ErrInvalidHeaderComment)
+}
+
+func TestWaylinesWriteKmz_Progress(t *testing.T) {
+	waylines := waylinesWithActionsAt("Progress", 3, 0)
+
+	var completed []int
+	var buffer bytes.Buffer
+	err := waylines.WriteKmz(&buffer, RenderOptions{
+		Progress: func(done, total int) {
+			assert.Equal(t, 8, total, "two passes over three placemarks plus two archive entries")
+			completed = append(completed, done)
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, completed)
+
+	_, err = ParseKMZBuffer(buffer.Bytes())
+	assert.NoError(t, err)
+}
+
+func TestWriteKmz_MatchesCreateKmzBuffer(t *testing.T) {
+	mission, err := ConvertWaylinesToWPMLMission(createValidWaylines("Stream"))
+	require.NoError(t, err)
+
+	var streamed bytes.Buffer
+	require.NoError(t, WriteKmz(mission, &streamed))
+	buffered, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+
+	assert.Equal(t, buffered.Bytes(), streamed.Bytes())
+}
+
+func TestRenderOptions_DeterministicOutput(t *testing.T) {
+	renderTime := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	newOptions := func() RenderOptions {
+		next := 100
+		return RenderOptions{
+			Clock: func() time.Time { return renderTime },
+			IDSource: func() int {
+				next += 10
+				return next
+			},
+		}
+	}
+	render := func() ([]byte, []byte) {
+		var kmz, tarGz bytes.Buffer
+		require.NoError(t, waylinesWithActionsAt("Golden", 3, 0, 2).WriteKmz(&kmz, newOptions()))
+
+		mission, err := ConvertWaylinesToWPMLMissionWithOptions(waylinesWithActionsAt("Golden", 3, 0, 2), newOptions())
+		require.NoError(t, err)
+		require.NoError(t, WriteWPMZTarGz(mission, &tarGz))
+		return kmz.Bytes(), tarGz.Bytes()
+	}
+
+	firstKmz, firstTarGz := render()
+	time.Sleep(2 * time.Millisecond)
+	secondKmz, secondTarGz := render()
+	assert.Equal(t, firstKmz, secondKmz)
+	assert.Equal(t, firstTarGz, secondTarGz)
+
+	parsed, err := ParseKMZBuffer(firstKmz)
+	require.NoError(t, err)
+	assert.Equal(t, renderTime.UnixMilli(), parsed.Template.Document.CreateTime)
+	assert.Equal(t, renderTime.UnixMilli(), parsed.Template.Document.UpdateTime)
+	assert.Equal(t, map[int]int{0: 110, 2: 120}, waylineActionGroupIDs(parsed))
+
+	zipReader, err := zip.NewReader(bytes.NewReader(firstKmz), int64(len(firstKmz)))
+	require.NoError(t, err)
+	for _, file := range zipReader.File {
+		assert.True(t, renderTime.Equal(file.Modified), file.Name)
+	}
+}
+
+func TestRenderOptions_ConflictingIDSources(t *testing.T) {
+	_, err := ConvertWaylinesToWPMLMissionWithOptions(createValidWaylines("Conflict"), RenderOptions{
+		ActionGroupIDAllocator: NewMonotonicActionGroupIDAllocator(),
+		IDSource:               func() int { return 1 },
+	})
+	assert.ErrorIs(t, err, ErrConflictingIDSources)
+}
@@ -74,6 +74,7 @@ func TestCreateKmzBuffer(t *testing.T) {
 		TemplateType:            TemplateTypeWaypoint,
 		GlobalHeight:            80.0,
 		GlobalSpeed:             8.0,
+		PhotoSettings:           []string{"wide"},
 		ClimbMode:               "vertical",
 		SafeHeight:              30.0,
 		GlobalRTHHeight:         120.0,
@@ -0,0 +1,66 @@
+package wpml
+
+import "fmt"
+
+// Mapping overlap bounds, in percent. DJI accepts overlaps up to
+// MaxMappingOverlap; below MinPracticalMappingOverlap photos usually share too
+// few features to stitch into a clean orthomosaic.
+const (
+	MinMappingOverlap          = 0
+	MaxMappingOverlap          = 90
+	MinPracticalMappingOverlap = 60
+)
+
+type mappingOverlap struct {
+	name  string
+	value int
+}
+
+// overlaps returns the configured overlaps with the names used in errors and
+// warnings.
+func (m *MappingConfig) overlaps() []mappingOverlap {
+	return []mappingOverlap{
+		{name: "front", value: m.FrontOverlap},
+		{name: "side", value: m.SideOverlap},
+	}
+}
+
+func validateMappingOverlap(w *Waylines) error {
+	if w.Mapping == nil {
+		return nil
+	}
+	for _, overlap := range w.Mapping.overlaps() {
+		if overlap.value < MinMappingOverlap || overlap.value > MaxMappingOverlap {
+			return fmt.Errorf(ErrMappingOverlapOutOfRange, overlap.name, overlap.value, MinMappingOverlap, MaxMappingOverlap)
+		}
+	}
+	return nil
+}
+
+func mappingOverlapWarnings(w *Waylines) []Warning {
+	if w.Mapping == nil {
+		return nil
+	}
+	var warnings []Warning
+	for _, overlap := range w.Mapping.overlaps() {
+		if overlap.value < MinPracticalMappingOverlap {
+			warnings = append(warnings, Warning{
+				Rule:    WarningRuleLowMappingOverlap,
+				Message: fmt.Sprintf(ErrLowMappingOverlap, overlap.name, overlap.value, MinPracticalMappingOverlap),
+			})
+		}
+	}
+	return warnings
+}
+
+// convertToOverlap emits the mapping overlaps as the camera overlaps of the
+// template, where H runs along the flight line and W across it.
+func convertToOverlap(waylines *Waylines) *Overlap {
+	if waylines.Mapping == nil {
+		return nil
+	}
+	return &Overlap{
+		OrthoCameraOverlapH: intPtr(waylines.Mapping.FrontOverlap),
+		OrthoCameraOverlapW: intPtr(waylines.Mapping.SideOverlap),
+	}
+}
@@ -0,0 +1,63 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mappingWaylines(front, side int) *Waylines {
+	waylines := createValidWaylines("Survey")
+	waylines.TemplateType = TemplateTypeMapping2D
+	waylines.Mapping = &MappingConfig{FrontOverlap: front, SideOverlap: side}
+	return waylines
+}
+
+func TestValidateMappingOverlap(t *testing.T) {
+	tests := []struct {
+		name          string
+		front, side   int
+		expectedError string
+	}{
+		{name: "Typical overlaps", front: 80, side: 70},
+		{name: "Upper bound", front: 90, side: 90},
+		{name: "Front above range", front: 95, side: 70, expectedError: "mapping front overlap 95% is outside"},
+		{name: "Side negative", front: 80, side: -10, expectedError: "mapping side overlap -10% is outside"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mappingWaylines(tt.front, tt.side).Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestMappingOverlapWarnings(t *testing.T) {
+	assert.Empty(t, mappingOverlapWarnings(mappingWaylines(80, 70)))
+
+	warnings := mappingOverlapWarnings(mappingWaylines(80, 40))
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarningRuleLowMappingOverlap, warnings[0].Rule)
+	assert.Contains(t, warnings[0].Message, "mapping side overlap 40%")
+}
+
+func TestConvert_MappingOverlap(t *testing.T) {
+	mission, err := ConvertWaylinesToWPMLMission(mappingWaylines(80, 70))
+	require.NoError(t, err)
+
+	overlap := mission.Template.Document.Folders[0].Overlap
+	require.NotNil(t, overlap)
+	assert.Equal(t, 80, *overlap.OrthoCameraOverlapH)
+	assert.Equal(t, 70, *overlap.OrthoCameraOverlapW)
+
+	mission, err = ConvertWaylinesToWPMLMission(createValidWaylines("Waypoints"))
+	require.NoError(t, err)
+	assert.Nil(t, mission.Template.Document.Folders[0].Overlap)
+}
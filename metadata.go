@@ -0,0 +1,101 @@
+package wpml
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// Metadata limits. Keys are restricted to characters that are safe in XML
+// names so they can be used as element or attribute names by downstream
+// tools; values may hold any text XML can carry.
+const (
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 1024
+)
+
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// MissionMetadata carries caller-defined key/value pairs, such as job or
+// customer IDs, in the template. DJI Pilot and the aircraft ignore it.
+type MissionMetadata struct {
+	Entries []MetadataEntry `xml:"wpml:entry" json:"entries"`
+}
+
+type MetadataEntry struct {
+	Key   string `xml:"wpml:key" json:"key"`
+	Value string `xml:"wpml:value" json:"value"`
+}
+
+// SetMetadata stores metadata in the template, sorted by key so the rendered
+// file is deterministic. An empty map removes the metadata element.
+func (m *WPMLMission) SetMetadata(metadata map[string]string) {
+	if m.Template == nil {
+		return
+	}
+	if len(metadata) == 0 {
+		m.Template.Document.Metadata = nil
+		return
+	}
+
+	entries := make([]MetadataEntry, 0, len(metadata))
+	for _, key := range sortedMetadataKeys(metadata) {
+		entries = append(entries, MetadataEntry{Key: key, Value: metadata[key]})
+	}
+	m.Template.Document.Metadata = &MissionMetadata{Entries: entries}
+}
+
+// Metadata returns the metadata stored in the template, or nil if there is
+// none.
+func (m *WPMLMission) Metadata() map[string]string {
+	if m.Template == nil || m.Template.Document.Metadata == nil {
+		return nil
+	}
+	metadata := make(map[string]string, len(m.Template.Document.Metadata.Entries))
+	for _, entry := range m.Template.Document.Metadata.Entries {
+		metadata[entry.Key] = entry.Value
+	}
+	return metadata
+}
+
+func validateMetadata(w *Waylines) error {
+	for _, key := range sortedMetadataKeys(w.Metadata) {
+		if len(key) > MaxMetadataKeyLength || !metadataKeyPattern.MatchString(key) {
+			return fmt.Errorf(ErrInvalidMetadataKey, key, MaxMetadataKeyLength)
+		}
+		value := w.Metadata[key]
+		if length := utf8.RuneCountInString(value); length > MaxMetadataValueLength {
+			return fmt.Errorf(ErrMetadataValueTooLong, key, length, MaxMetadataValueLength)
+		}
+		if !isXMLText(value) {
+			return fmt.Errorf(ErrInvalidMetadataValue, key)
+		}
+	}
+	return nil
+}
+
+// isXMLText reports whether s only contains characters allowed in XML 1.0
+// documents; escaping cannot represent the others.
+func isXMLText(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+		case r < 0x20, r >= 0xD800 && r <= 0xDFFF, r == 0xFFFE, r == 0xFFFF:
+			return false
+		}
+	}
+	return true
+}
+
+func sortedMetadataKeys(metadata map[string]string) []string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package wpml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata_RoundTrip(t *testing.T) {
+	waylines := createValidWaylines("Metadata")
+	waylines.Metadata = map[string]string{
+		"job_id":           "J-1042",
+		"customer.id":      "ACME & Sons <north>",
+		"pipeline-version": "3.2.1",
+	}
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	entries := mission.Template.Document.Metadata.Entries
+	require.Len(t, entries, 3)
+	assert.Equal(t, "customer.id", entries[0].Key)
+
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, waylines.Metadata, parsed.Metadata())
+}
+
+func TestMetadata_Omitted(t *testing.T) {
+	mission, err := ConvertWaylinesToWPMLMission(createValidWaylines("No Metadata"))
+	require.NoError(t, err)
+	assert.Nil(t, mission.Template.Document.Metadata)
+	assert.Nil(t, mission.Metadata())
+
+	data, err := MarshalTemplate(mission.Template)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "wpml:metadata")
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name          string
+		metadata      map[string]string
+		expectedError string
+	}{
+		{name: "Valid keys", metadata: map[string]string{"job_id": "1", "_internal.v2": "x", "multi-line": "a\nb"}},
+		{name: "Empty key", metadata: map[string]string{"": "x"}, expectedError: `metadata key ""`},
+		{name: "Key with space", metadata: map[string]string{"job id": "x"}, expectedError: `metadata key "job id"`},
+		{name: "Key starting with digit", metadata: map[string]string{"1job": "x"}, expectedError: `metadata key "1job"`},
+		{name: "Key too long", metadata: map[string]string{strings.Repeat("k", MaxMetadataKeyLength+1): "x"}, expectedError: "metadata key"},
+		{name: "Value too long", metadata: map[string]string{"notes": strings.Repeat("v", MaxMetadataValueLength+1)}, expectedError: `metadata value of "notes" is 1025 characters`},
+		{name: "Control character in value", metadata: map[string]string{"notes": "a\x00b"}, expectedError: `metadata value of "notes" contains characters`},
+		{name: "Invalid UTF-8 in value", metadata: map[string]string{"notes": "a\xffb"}, expectedError: `metadata value of "notes" contains characters`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Metadata")
+			waylines.Metadata = tt.metadata
+
+			err := waylines.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
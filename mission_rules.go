@@ -0,0 +1,434 @@
+package wpml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+type missionRule struct {
+	check func(w *Waylines) error
+	// actions marks rules that cross-check waypoint actions; ValidateDraft
+	// skips them.
+	actions bool
+}
+
+var missionRules = []missionRule{
+	{check: validateWaypointHeights},
+	{check: validateCombinedYaw, actions: true},
+	{check: validateGimbalRotateMode, actions: true},
+	{check: validateActionTriggers, actions: true},
+	{check: validateHeadingPOI},
+	{check: validateMetadata},
+	{check: validatePhotoSettings, actions: true},
+	{check: validateDefaultTakeoffClearance},
+	{check: validateTakeOffRefPointHeightMode},
+	{check: validateWorkTypeTurnModes},
+	{check: validateWaypointEndType},
+	{check: validateTurnDampingForm},
+	{check: validateTurnDamping},
+	{check: validatePayloads},
+	{check: validateLiDARSettings},
+	{check: validateMappingOverlap},
+	{check: validateActionGroupCount, actions: true},
+}
+
+func (w *Waylines) validateMissionRules(draft bool) error {
+	for _, rule := range missionRules {
+		if draft && rule.actions {
+			continue
+		}
+		if err := rule.check(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCombinedYaw tracks the aircraft heading set by rotateYaw actions and
+// checks that north-referenced gimbal yaw targets stay within the payload's
+// mechanical yaw range relative to the body. The offset of a north-referenced
+// target is the target minus the aircraft heading, and it is accepted when
+// any angle pointing the same way is within the range. The heading only
+// carries over to later waypoints under yaw modes that hold it; when the
+// aircraft follows the route or faces a point of interest it is unknown again
+// at the next waypoint.
+func validateCombinedYaw(w *Waylines) error {
+	capabilities, ok := CapabilitiesForPayload(w.PayloadModel)
+	if !ok || capabilities.GimbalYawRange == nil {
+		return nil
+	}
+	yawRange := *capabilities.GimbalYawRange
+
+	headingMode := convertGlobalHeadingParam(w).WaypointHeadingMode
+	holdsHeading := headingMode == HeadingModeFree || headingMode == HeadingModeManually
+
+	var aircraftHeading *float64
+	for i, wp := range w.Waypoints {
+		if !holdsHeading {
+			aircraftHeading = nil
+		}
+		for _, actionReq := range wp.Actions {
+			relativeYaw, checked := 0.0, false
+			contains := yawRange.containsDirection
+
+			switch action := actionReq.Action.(type) {
+			case *RotateYawAction:
+				heading := action.AircraftHeading
+				aircraftHeading = &heading
+			case *GimbalRotateAction:
+				if !action.GimbalYawRotateEnable {
+					continue
+				}
+				if action.GimbalHeadingYawBase == GimbalHeadingYawBaseAircraft {
+					relativeYaw, checked, contains = action.GimbalYawRotateAngle, true, yawRange.Contains
+				} else if aircraftHeading != nil {
+					relativeYaw, checked = normalizeAngle(action.GimbalYawRotateAngle-*aircraftHeading), true
+				}
+			case *OrientedShootAction:
+				relativeYaw, checked = normalizeAngle(action.GimbalYawRotateAngle-action.AircraftHeading), true
+			case *AccurateShootAction:
+				relativeYaw, checked = normalizeAngle(action.GimbalYawRotateAngle-action.AircraftHeading), true
+			}
+
+			if checked && !contains(relativeYaw) {
+				return fmt.Errorf(ErrCombinedYawOutOfRange,
+					i, actionReq.Type, relativeYaw, w.PayloadModel, yawRange.Min, yawRange.Max)
+			}
+		}
+	}
+
+	return nil
+}
+
+type heightRange struct {
+	min float64
+	max float64
+	// reference names what heights in the mode are measured from when a
+	// height of zero or less would put the aircraft at or below it. It is
+	// empty for absolute modes.
+	reference string
+}
+
+// waypointHeightRanges are the accepted waypoint heights per height mode.
+// Relative heights keep DJI's 5–500 m window; absolute EGM96 heights span
+// sea-level depressions to high-altitude terrain; terrain-relative heights may
+// legitimately sit close to the surface.
+var waypointHeightRanges = map[HeightMode]heightRange{
+	HeightModeRelativeToStartPoint:  {min: 5, max: 500, reference: "takeoff point"},
+	HeightModeEGM96:                 {min: -500, max: 9000},
+	HeightModeAboveGroundLevel:      {min: 1, max: 1500, reference: "ground"},
+	HeightModeRealTimeFollowSurface: {min: 1, max: 1500, reference: "ground"},
+}
+
+func validateWaypointHeights(w *Waylines) error {
+	for i := range w.Waypoints {
+		if err := w.validateWaypointHeight(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateWaypointHeight checks the height of waypoint i against the range of
+// its height mode and, when that differs from the mission mode, that it
+// converts into a height the mission mode accepts.
+func (w *Waylines) validateWaypointHeight(i int) error {
+	wp := w.Waypoints[i]
+	mode := w.waypointHeightMode(i)
+	limits, ok := waypointHeightRanges[mode]
+	if !ok {
+		return fmt.Errorf(ErrUnknownHeightMode, i, mode)
+	}
+	if limits.reference != "" && wp.Height <= 0 {
+		return fmt.Errorf(ErrWaypointHeightBelowReference, i, wp.Height, limits.reference, mode)
+	}
+	if wp.Height < limits.min || wp.Height > limits.max {
+		return fmt.Errorf(ErrWaypointHeightOutOfRange, i, wp.Height, limits.min, limits.max, mode)
+	}
+
+	if mode == w.heightMode() {
+		return nil
+	}
+	height, err := w.missionHeight(wp)
+	if err != nil {
+		return fmt.Errorf(ErrWaypointHeightMode, i, err)
+	}
+	missionLimits := waypointHeightRanges[w.heightMode()]
+	if height < missionLimits.min || height > missionLimits.max {
+		return fmt.Errorf(ErrConvertedHeightOutOfRange, i, wp.Height, mode, height, w.heightMode(), missionLimits.min, missionLimits.max)
+	}
+	return nil
+}
+
+// validateWorkTypeTurnModes rejects pass-through turn modes in stop-and-go
+// missions, where the aircraft must come to a stop at every waypoint.
+func validateWorkTypeTurnModes(w *Waylines) error {
+	if w.WorkType != WorkTypeStopAndGo {
+		return nil
+	}
+	if isPassTurnMode(w.GlobalWaypointTurnMode) {
+		return fmt.Errorf(ErrStopAndGoGlobalTurnMode, w.GlobalWaypointTurnMode)
+	}
+	for i, wp := range w.Waypoints {
+		if isPassTurnMode(wp.WaypointTurnMode) {
+			return fmt.Errorf(ErrStopAndGoWaypointTurnMode, i, wp.WaypointTurnMode)
+		}
+	}
+	return nil
+}
+
+// validateWaypointEndType rejects end types the last waypoint's own turn mode
+// contradicts. Global turn modes are overridden on the last waypoint instead.
+func validateWaypointEndType(w *Waylines) error {
+	if w.WaypointEndType == "" || len(w.Waypoints) == 0 {
+		return nil
+	}
+	if w.WaypointEndType == WaypointEndTypePass && w.WorkType == WorkTypeStopAndGo {
+		return errors.New(ErrStopAndGoWaypointEndType)
+	}
+
+	last := len(w.Waypoints) - 1
+	turnMode := w.Waypoints[last].WaypointTurnMode
+	if turnMode == "" {
+		return nil
+	}
+	if isPassTurnMode(turnMode) != (w.WaypointEndType == WaypointEndTypePass) {
+		return fmt.Errorf(ErrWaypointEndTypeTurnMode, w.WaypointEndType, turnMode, last)
+	}
+	return nil
+}
+
+func isPassTurnMode(turnMode string) bool {
+	return turnMode == TurnModeCoordinateTurn || turnMode == TurnModeToPointAndPassWithContinuityCurvature
+}
+
+// DefaultTakeoffClearance is the minimum clearance above the takeoff point that
+// Validate enforces, matching DJI's 5 m floor for relative heights.
+const DefaultTakeoffClearance = 5.0
+
+// ValidateTakeoffClearance checks that the lowest waypoint is at least
+// minClearance meters above the takeoff point. In relativeToStartPoint mode the
+// waypoint height is the clearance; in EGM96 mode the clearance is the waypoint
+// height minus TakeOffRefPointHeight, and the check needs a takeoff reference
+// point. Terrain-relative modes cannot be related to the takeoff point without
+// elevation data and are not checked.
+func (w *Waylines) ValidateTakeoffClearance(minClearance float64) error {
+	var reference float64
+	switch w.heightMode() {
+	case HeightModeRelativeToStartPoint:
+		reference = 0
+	case HeightModeEGM96:
+		if !w.hasTakeOffRefPoint() {
+			return nil
+		}
+		reference = w.TakeOffRefPointHeight
+	default:
+		return nil
+	}
+
+	lowest := -1
+	for i, wp := range w.Waypoints {
+		if lowest < 0 || w.waypointHeight(wp) < w.waypointHeight(w.Waypoints[lowest]) {
+			lowest = i
+		}
+	}
+	if lowest < 0 {
+		return nil
+	}
+
+	clearance := w.waypointHeight(w.Waypoints[lowest]) - reference
+	if clearance < minClearance {
+		return fmt.Errorf(ErrTakeoffClearanceTooLow, lowest, clearance, minClearance, w.heightMode())
+	}
+
+	return nil
+}
+
+func validateDefaultTakeoffClearance(w *Waylines) error {
+	return w.ValidateTakeoffClearance(DefaultTakeoffClearance)
+}
+
+// validateTakeOffRefPointHeightMode checks that the takeoff reference heights
+// use the reference the mission height mode relates waypoint heights to:
+// EGM96 missions need the absolute height, terrain-relative missions the AGL
+// height. A relative mission accepts either, since its heights are measured
+// from the takeoff point itself.
+func validateTakeOffRefPointHeightMode(w *Waylines) error {
+	mode := w.heightMode()
+	agl := w.TakeOffRefPointAGLHeight
+
+	if !w.hasTakeOffRefPoint() {
+		if w.TakeOffRefPointHeight != 0 || agl != nil {
+			aglText := "unset"
+			if agl != nil {
+				aglText = fmt.Sprintf("%.1fm", *agl)
+			}
+			return fmt.Errorf(ErrTakeOffRefHeightWithoutPoint, mode, w.TakeOffRefPointHeight, aglText)
+		}
+		return nil
+	}
+
+	switch mode {
+	case HeightModeEGM96:
+		if agl != nil {
+			return fmt.Errorf(ErrTakeOffRefAGLHeightInEGM96, *agl, w.TakeOffRefPointHeight)
+		}
+	case HeightModeAboveGroundLevel, HeightModeRealTimeFollowSurface:
+		if agl == nil && w.TakeOffRefPointHeight != 0 {
+			return fmt.Errorf(ErrTakeOffRefMissingAGLHeight, mode, w.TakeOffRefPointHeight)
+		}
+	}
+	return nil
+}
+
+func (w *Waylines) heightMode() HeightMode {
+	if w.HeightType == "" {
+		return HeightModeRelativeToStartPoint
+	}
+	return w.HeightType
+}
+
+// waypointHeightMode returns the height mode of waypoint i, which inherits the
+// mission HeightType unless the waypoint sets its own.
+func (w *Waylines) waypointHeightMode(i int) HeightMode {
+	if mode := w.Waypoints[i].HeightMode; mode != "" {
+		return mode
+	}
+	return w.heightMode()
+}
+
+// missionHeight returns the height of waypoint in the mission height mode, the
+// only height reference the converter emits. Relative and EGM96 heights convert
+// through TakeOffRefPointHeight, the EGM96 height of the takeoff point; heights
+// above the terrain cannot be related to either without elevation data.
+func (w *Waylines) missionHeight(waypoint WaylinesWaypoint) (float64, error) {
+	from, to := waypoint.HeightMode, w.heightMode()
+	if from == "" || from == to {
+		return waypoint.Height, nil
+	}
+
+	relativeToEGM96 := from == HeightModeRelativeToStartPoint && to == HeightModeEGM96
+	egm96ToRelative := from == HeightModeEGM96 && to == HeightModeRelativeToStartPoint
+	if !relativeToEGM96 && !egm96ToRelative {
+		return 0, fmt.Errorf(ErrHeightModeNotConvertible, from, to)
+	}
+	if !w.hasTakeOffRefPoint() {
+		return 0, fmt.Errorf(ErrHeightModeNeedsTakeOffRef, from, to)
+	}
+	if relativeToEGM96 {
+		return waypoint.Height + w.TakeOffRefPointHeight, nil
+	}
+	return waypoint.Height - w.TakeOffRefPointHeight, nil
+}
+
+func (w *Waylines) hasTakeOffRefPoint() bool {
+	return w.TakeOffRefPointLatitude != 0 && w.TakeOffRefPointLongitude != 0
+}
+
+// normalizeAngle wraps an angle in degrees into [-180, 180].
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle+180, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle - 180
+}
+
+// validateLiDARSettings rejects LiDAR settings on payloads without a LiDAR and
+// checks each configured setting against the payload's supported values.
+func validateLiDARSettings(w *Waylines) error {
+	if w.LiDAR == nil {
+		return nil
+	}
+	capabilities, _ := CapabilitiesForPayload(w.PayloadModel)
+	lidar := capabilities.LiDAR
+	if lidar == nil {
+		return fmt.Errorf(ErrLiDARSettingsUnsupported, w.PayloadModel)
+	}
+
+	if w.LiDAR.ReturnMode != "" && !slices.Contains(lidar.ReturnModes, w.LiDAR.ReturnMode) {
+		return fmt.Errorf(ErrLiDARSettingUnsupported, w.PayloadModel, "return mode", w.LiDAR.ReturnMode, lidar.ReturnModes)
+	}
+	if w.LiDAR.ScanningMode != "" && !slices.Contains(lidar.ScanningModes, w.LiDAR.ScanningMode) {
+		return fmt.Errorf(ErrLiDARSettingUnsupported, w.PayloadModel, "scanning mode", w.LiDAR.ScanningMode, lidar.ScanningModes)
+	}
+	if w.LiDAR.SamplingRate != 0 && !slices.Contains(lidar.SamplingRates, w.LiDAR.SamplingRate) {
+		return fmt.Errorf(ErrLiDARSettingUnsupported, w.PayloadModel, "sampling rate", w.LiDAR.SamplingRate, lidar.SamplingRates)
+	}
+	return nil
+}
+
+// validateGimbalRotateMode requires an explicit rotate mode on gimbalRotate
+// actions that set an angle. Firmware versions disagree on the default, so
+// without one the gimbal can end up at a different angle than planned.
+func validateGimbalRotateMode(w *Waylines) error {
+	for i, wp := range w.Waypoints {
+		for _, actionReq := range wp.Actions {
+			action, ok := actionReq.Action.(*GimbalRotateAction)
+			if !ok || action.GimbalRotateMode != "" {
+				continue
+			}
+			var axis string
+			switch {
+			case action.GimbalPitchRotateEnable:
+				axis = "pitch"
+			case action.GimbalRollRotateEnable:
+				axis = "roll"
+			case action.GimbalYawRotateEnable:
+				axis = "yaw"
+			default:
+				continue
+			}
+			return fmt.Errorf(ErrGimbalRotateModeRequired, i, axis, GimbalRotateModeAbsoluteAngle)
+		}
+	}
+	return nil
+}
+
+// validateHeadingPOI requires a heading POI under towardPOI and checks that a
+// POI, which is written as wpml:waypointPoiPoint whenever it is set, has
+// coordinates in the ranges waypoints accept.
+func validateHeadingPOI(w *Waylines) error {
+	poi := w.HeadingPOI
+	if poi == nil {
+		if w.AircraftYawMode == HeadingModeTowardPOI {
+			return ErrHeadingPOIRequired
+		}
+		return nil
+	}
+	if !(poi.Latitude >= -90 && poi.Latitude <= 90) || !(poi.Longitude >= -180 && poi.Longitude <= 180) {
+		return fmt.Errorf(ErrHeadingPOIOutOfRange, poi.Latitude, poi.Longitude)
+	}
+	return nil
+}
+
+// validatePhotoSettings requires a lens selection for every capture action:
+// either the mission PhotoSettings or a lens index on the action itself.
+// Without one the camera falls back to a firmware-dependent lens.
+func validatePhotoSettings(w *Waylines) error {
+	if len(w.PhotoSettings) > 0 {
+		return nil
+	}
+	for i := range w.Waypoints {
+		for _, group := range w.actionGroups(i) {
+			for _, action := range group.actions {
+				if isCaptureAction(action.Type) && !hasOwnLensIndex(action) {
+					return fmt.Errorf(ErrPhotoSettingsRequired, i, action.Type)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasOwnLensIndex(action ActionRequest) bool {
+	switch a := action.Action.(type) {
+	case *TakePhotoAction:
+		return !a.UseGlobalPayloadLensIndex && a.PayloadLensIndex != nil && *a.PayloadLensIndex != ""
+	case *PanoShotAction:
+		return !a.UseGlobalPayloadLensIndex && a.PayloadLensIndex != nil && *a.PayloadLensIndex != ""
+	}
+	return false
+}
@@ -0,0 +1,523 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gimbalYawAction(base string, yaw float64) ActionRequest {
+	return ActionRequest{
+		Type: ActionTypeGimbalRotate,
+		Action: &GimbalRotateAction{
+			GimbalHeadingYawBase:  base,
+			GimbalRotateMode:      "absoluteAngle",
+			GimbalYawRotateEnable: true,
+			GimbalYawRotateAngle:  yaw,
+		},
+	}
+}
+
+func rotateYawAction(heading float64) ActionRequest {
+	return ActionRequest{
+		Type:   ActionTypeRotateYaw,
+		Action: &RotateYawAction{AircraftHeading: heading},
+	}
+}
+
+func TestValidateCombinedYaw(t *testing.T) {
+	tests := []struct {
+		name        string
+		yawMode     string
+		actions     [][]ActionRequest
+		expectError string
+	}{
+		{
+			name: "Gimbal yaw within range of aircraft heading",
+			actions: [][]ActionRequest{
+				{rotateYawAction(90), gimbalYawAction(GimbalHeadingYawBaseNorth, 120)},
+			},
+		},
+		{
+			name: "Combined yaw exceeds gimbal range",
+			actions: [][]ActionRequest{
+				{rotateYawAction(90), gimbalYawAction(GimbalHeadingYawBaseNorth, -90)},
+			},
+			expectError: "-180.0°",
+		},
+		{
+			name:    "Aircraft heading carries over to later waypoints",
+			yawMode: "free",
+			actions: [][]ActionRequest{
+				{rotateYawAction(-120)},
+				{gimbalYawAction(GimbalHeadingYawBaseNorth, 60)},
+			},
+			expectError: "waypoint 1",
+		},
+		{
+			name:    "Following the route resets the aircraft heading",
+			yawMode: HeadingModeFollowWayline,
+			actions: [][]ActionRequest{
+				{rotateYawAction(-120)},
+				{gimbalYawAction(GimbalHeadingYawBaseNorth, 60)},
+			},
+		},
+		{
+			name: "North-referenced yaw without known heading is not checked",
+			actions: [][]ActionRequest{
+				{gimbalYawAction(GimbalHeadingYawBaseNorth, 170)},
+			},
+		},
+		{
+			name: "Aircraft-referenced yaw is checked directly",
+			actions: [][]ActionRequest{
+				{gimbalYawAction(GimbalHeadingYawBaseAircraft, 100)},
+			},
+			expectError: "100.0°",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Combined Yaw")
+			waylines.DroneModel = DroneM30
+			waylines.PayloadModel = PayloadM30Camera
+			if tt.yawMode != "" {
+				waylines.AircraftYawMode = tt.yawMode
+			}
+			base := waylines.Waypoints[0]
+			waylines.Waypoints = nil
+			for i, actions := range tt.actions {
+				wp := base
+				wp.Latitude += float64(i) * 0.001
+				wp.Actions = actions
+				waylines.Waypoints = append(waylines.Waypoints, wp)
+			}
+
+			err := waylines.Validate()
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectError)
+		})
+	}
+}
+
+func TestValidateCombinedYaw_OffsetWrapsAround(t *testing.T) {
+	waylines := createValidWaylines("Combined Yaw")
+	waylines.DroneModel = DroneM30
+	waylines.PayloadModel = PayloadM30Camera
+	// 170° from a -170° heading is 340° clockwise, the same direction as
+	// -20° from the body.
+	waylines.Waypoints[0].Actions = []ActionRequest{
+		rotateYawAction(-170), gimbalYawAction(GimbalHeadingYawBaseNorth, 170),
+	}
+	assert.NoError(t, waylines.Validate())
+
+	waylines.Waypoints[0].Actions[1] = gimbalYawAction(GimbalHeadingYawBaseNorth, 60)
+	err := waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-130.0°")
+}
+
+func TestAngleRange_ContainsDirection(t *testing.T) {
+	wide := AngleRange{Min: -320, Max: 320}
+	assert.True(t, wide.containsDirection(340))
+	assert.True(t, wide.containsDirection(-700))
+
+	narrow := AngleRange{Min: -90, Max: 90}
+	assert.True(t, narrow.containsDirection(340))
+	assert.True(t, narrow.containsDirection(-270))
+	assert.False(t, narrow.containsDirection(180))
+	assert.False(t, narrow.containsDirection(-100))
+}
+
+func TestValidateCombinedYaw_PayloadWithoutYawAxis(t *testing.T) {
+	waylines := createValidWaylines("Combined Yaw")
+	waylines.Waypoints[0].Actions = []ActionRequest{
+		rotateYawAction(90), gimbalYawAction(GimbalHeadingYawBaseNorth, -90),
+	}
+
+	assert.NoError(t, waylines.Validate())
+}
+
+func TestNormalizeAngle(t *testing.T) {
+	assert.InDelta(t, 0.0, normalizeAngle(360), 1e-9)
+	assert.InDelta(t, -170.0, normalizeAngle(190), 1e-9)
+	assert.InDelta(t, 170.0, normalizeAngle(-190), 1e-9)
+	assert.InDelta(t, 90.0, normalizeAngle(90), 1e-9)
+}
+
+func TestValidateTakeoffClearance(t *testing.T) {
+	t.Run("Relative mode uses waypoint height", func(t *testing.T) {
+		waylines := createValidWaylines("Clearance")
+		waylines.Waypoints[0].Height = 8
+
+		assert.NoError(t, waylines.ValidateTakeoffClearance(5))
+
+		err := waylines.ValidateTakeoffClearance(10)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoint 0 is 8.0m")
+	})
+
+	t.Run("EGM96 mode subtracts takeoff height", func(t *testing.T) {
+		waylines := createValidWaylines("Clearance")
+		waylines.HeightType = HeightModeEGM96
+		waylines.TakeOffRefPointLatitude = 39.9
+		waylines.TakeOffRefPointLongitude = 116.3
+		waylines.TakeOffRefPointHeight = 48
+		second := waylines.Waypoints[0]
+		second.Height = 120
+		waylines.Waypoints = append(waylines.Waypoints, second)
+
+		err := waylines.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoint 0 is 2.0m above the takeoff point")
+		assert.Contains(t, err.Error(), "EGM96")
+
+		waylines.TakeOffRefPointHeight = 40
+		assert.NoError(t, waylines.Validate())
+	})
+
+	t.Run("EGM96 mode without takeoff point is not checked", func(t *testing.T) {
+		waylines := createValidWaylines("Clearance")
+		waylines.HeightType = HeightModeEGM96
+		waylines.TakeOffRefPointHeight = 48
+
+		assert.NoError(t, waylines.ValidateTakeoffClearance(5))
+	})
+
+	t.Run("Terrain-relative mode is not checked", func(t *testing.T) {
+		waylines := createValidWaylines("Clearance")
+		waylines.HeightType = HeightModeAboveGroundLevel
+
+		assert.NoError(t, waylines.ValidateTakeoffClearance(1000))
+	})
+}
+
+func TestValidateWorkTypeTurnModes(t *testing.T) {
+	waylines := createValidWaylines("Stop And Go")
+	waylines.WorkType = WorkTypeStopAndGo
+	assert.NoError(t, waylines.Validate())
+
+	waylines.GlobalWaypointTurnMode = TurnModeCoordinateTurn
+	err := waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "global turn mode coordinateTurn")
+
+	waylines.GlobalWaypointTurnMode = ""
+	waylines.Waypoints[0].WaypointTurnMode = TurnModeToPointAndPassWithContinuityCurvature
+	err = waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint 0")
+
+	waylines.WorkType = WorkTypeContinuous
+	assert.NoError(t, waylines.Validate())
+
+	waylines.WorkType = "hover"
+	assert.Error(t, waylines.Validate())
+}
+
+func TestValidateWaypointEndType(t *testing.T) {
+	waylines := waylinesAt("End Type", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	waylines.WaypointEndType = WaypointEndTypeStop
+	waylines.GlobalWaypointTurnMode = TurnModeCoordinateTurn
+	assert.NoError(t, waylines.Validate(), "the end type overrides the global turn mode")
+
+	waylines.Waypoints[1].WaypointTurnMode = TurnModeToPointAndPassWithContinuityCurvature
+	err := waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint end type stop conflicts with turn mode toPointAndPassWithContinuityCurvature of the last waypoint 1")
+
+	waylines.WaypointEndType = WaypointEndTypePass
+	assert.NoError(t, waylines.Validate())
+
+	waylines.Waypoints[1].WaypointTurnMode = TurnModeToPointAndStopWithContinuityCurvature
+	assert.Error(t, waylines.Validate())
+
+	waylines.Waypoints[1].WaypointTurnMode = ""
+	waylines.GlobalWaypointTurnMode = ""
+	waylines.WorkType = WorkTypeStopAndGo
+	err = waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint end type pass")
+
+	waylines.WorkType = WorkTypeContinuous
+	waylines.WaypointEndType = "overshoot"
+	assert.Error(t, waylines.Validate())
+}
+
+func TestValidateWaypointHeights(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        HeightMode
+		height      float64
+		expectError bool
+	}{
+		{name: "Relative within range", mode: HeightModeRelativeToStartPoint, height: 120},
+		{name: "Relative below 5m", mode: HeightModeRelativeToStartPoint, height: 2, expectError: true},
+		{name: "Relative above 500m", mode: HeightModeRelativeToStartPoint, height: 600, expectError: true},
+		{name: "Default mode is relative", mode: "", height: 600, expectError: true},
+		{name: "EGM96 alpine altitude", mode: HeightModeEGM96, height: 3200},
+		{name: "EGM96 below sea level", mode: HeightModeEGM96, height: -50},
+		{name: "EGM96 above ceiling", mode: HeightModeEGM96, height: 12000, expectError: true},
+		{name: "AGL close to terrain", mode: HeightModeAboveGroundLevel, height: 3},
+		{name: "Follow surface too high", mode: HeightModeRealTimeFollowSurface, height: 2000, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Heights")
+			waylines.HeightType = tt.mode
+			waylines.Waypoints[0].Height = tt.height
+
+			err := waylines.Validate()
+			if !tt.expectError {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "waypoint 0 height")
+			mode := tt.mode
+			if mode == "" {
+				mode = HeightModeRelativeToStartPoint
+			}
+			assert.Contains(t, err.Error(), string(mode))
+		})
+	}
+}
+
+func TestValidateWaypointHeights_AllEntryPoints(t *testing.T) {
+	waylines := createValidWaylines("Heights")
+	waylines.Waypoints[0].Height = 600
+
+	assert.Error(t, NewWPMLValidator().ValidateStruct(waylines))
+	assert.Error(t, Validate(waylines))
+	err := waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint 0 height 600.0m is outside the 5 to 500m range", "Validate names the range")
+
+	waylines.HeightType = HeightModeEGM96
+	waylines.Waypoints[0].Height = 0
+	assert.NoError(t, NewWPMLValidator().ValidateStruct(waylines), "0m is a valid EGM96 height")
+	require.NoError(t, waylines.Validate())
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	placemark := mission.Template.Document.Folders[0].Placemarks[0]
+	assert.Equal(t, 0, *placemark.UseGlobalHeight)
+	assert.Equal(t, 0.0, *placemark.Height)
+}
+
+func TestValidateWaypointHeights_BelowReference(t *testing.T) {
+	tests := []struct {
+		name          string
+		missionMode   HeightMode
+		waypointMode  HeightMode
+		height        float64
+		expectedError string
+	}{
+		{name: "Relative below takeoff", missionMode: HeightModeRelativeToStartPoint, height: -10, expectedError: "at or below the takeoff point, heights must be positive in relativeToStartPoint"},
+		{name: "AGL below ground", missionMode: HeightModeAboveGroundLevel, height: -1, expectedError: "at or below the ground, heights must be positive in aboveGroundLevel"},
+		{name: "Per-waypoint AGL in EGM96 mission", missionMode: HeightModeEGM96, waypointMode: HeightModeAboveGroundLevel, height: -50, expectedError: "waypoint 0 height -50.0m would put the aircraft at or below the ground"},
+		{name: "EGM96 allows negative heights", missionMode: HeightModeEGM96, height: -50},
+		{name: "Unknown mission mode", missionMode: "aboveSeaLevel", height: 50, expectedError: `waypoint 0 uses unknown height mode "aboveSeaLevel"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Heights")
+			waylines.HeightType = tt.missionMode
+			waylines.Waypoints[0].HeightMode = tt.waypointMode
+			waylines.Waypoints[0].Height = tt.height
+
+			err := validateWaypointHeights(waylines)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestWaypointHeightRanges_PositiveAboveReference(t *testing.T) {
+	for mode, limits := range waypointHeightRanges {
+		if limits.reference != "" {
+			assert.Greater(t, limits.min, 0.0, "height mode %s", mode)
+		}
+	}
+}
+
+func TestValidateLiDARSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     PayloadModel
+		settings    LiDARSettings
+		errContains string
+	}{
+		{name: "supported settings", payload: PayloadL2, settings: LiDARSettings{RecordPointCloud: true, ReturnMode: LiDARReturnModeTriple, SamplingRate: 80000}},
+		{name: "non-LiDAR payload", payload: PayloadH20T, settings: LiDARSettings{RecordPointCloud: true}, errContains: "only valid for LiDAR payloads"},
+		{name: "unsupported sampling rate", payload: PayloadL1, settings: LiDARSettings{SamplingRate: 80000}, errContains: "sampling rate 80000"},
+		{name: "unknown return mode", payload: PayloadL1, settings: LiDARSettings{ReturnMode: "quadReturn"}, errContains: "ReturnMode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("LiDAR")
+			waylines.DroneModel = DroneM300RTK
+			waylines.PayloadModel = tt.payload
+			waylines.LiDAR = &tt.settings
+
+			err := waylines.Validate()
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidatePhotoSettings(t *testing.T) {
+	lenses := "zoom"
+
+	tests := []struct {
+		name          string
+		photoSettings []string
+		actions       []ActionRequest
+		expectError   bool
+	}{
+		{name: "no capture actions", actions: []ActionRequest{{Type: ActionTypeHover, Action: &HoverAction{HoverTime: 2}}}},
+		{name: "capture with photo settings", photoSettings: []string{"wide"}, actions: []ActionRequest{photoAction()}},
+		{name: "capture without photo settings", actions: []ActionRequest{photoAction()}, expectError: true},
+		{
+			name: "capture with own lens index",
+			actions: []ActionRequest{{
+				Type:   ActionTypeTakePhoto,
+				Action: &TakePhotoAction{PayloadLensIndex: &lenses},
+			}},
+		},
+		{
+			name: "own lens index ignored when using global lens",
+			actions: []ActionRequest{{
+				Type:   ActionTypeTakePhoto,
+				Action: &TakePhotoAction{PayloadLensIndex: &lenses, UseGlobalPayloadLensIndex: true},
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Photo Settings")
+			waylines.PhotoSettings = tt.photoSettings
+			waylines.Waypoints[0].Actions = tt.actions
+
+			err := waylines.Validate()
+			if !tt.expectError {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "photo settings are required")
+			assert.NoError(t, waylines.ValidateDraft(), "drafts do not check actions")
+		})
+	}
+}
+
+func TestValidateTakeOffRefPointHeightMode(t *testing.T) {
+	agl := 2.5
+
+	tests := []struct {
+		name        string
+		mode        HeightMode
+		withPoint   bool
+		height      float64
+		aglHeight   *float64
+		errContains string
+	}{
+		{name: "relative with both heights", mode: HeightModeRelativeToStartPoint, withPoint: true, height: 48, aglHeight: &agl},
+		{name: "EGM96 with absolute height", mode: HeightModeEGM96, withPoint: true, height: 20},
+		{name: "EGM96 with AGL height", mode: HeightModeEGM96, withPoint: true, height: 20, aglHeight: &agl, errContains: "AGL height 2.5m does not apply to height mode EGM96"},
+		{name: "AGL with AGL height", mode: HeightModeAboveGroundLevel, withPoint: true, height: 48, aglHeight: &agl},
+		{name: "AGL with only absolute height", mode: HeightModeAboveGroundLevel, withPoint: true, height: 48, errContains: "needs TakeOffRefPointAGLHeight"},
+		{name: "heights without point", mode: HeightModeRelativeToStartPoint, aglHeight: &agl, errContains: "TakeOffRefPointAGLHeight 2.5m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Takeoff Reference")
+			waylines.HeightType = tt.mode
+			waylines.Waypoints[0].Height = 100
+			if tt.withPoint {
+				waylines.TakeOffRefPointLatitude = 39.909
+				waylines.TakeOffRefPointLongitude = 116.397
+			}
+			waylines.TakeOffRefPointHeight = tt.height
+			waylines.TakeOffRefPointAGLHeight = tt.aglHeight
+
+			err := waylines.Validate()
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidateGimbalRotateMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      *GimbalRotateAction
+		expectError string
+	}{
+		{
+			name:   "Pitch with absolute mode",
+			action: &GimbalRotateAction{GimbalHeadingYawBase: "north", GimbalRotateMode: GimbalRotateModeAbsoluteAngle, GimbalPitchRotateEnable: true, GimbalPitchRotateAngle: -45},
+		},
+		{
+			name:   "No angle without mode",
+			action: &GimbalRotateAction{GimbalHeadingYawBase: "north"},
+		},
+		{
+			name:        "Pitch without mode",
+			action:      &GimbalRotateAction{GimbalHeadingYawBase: "north", GimbalPitchRotateEnable: true, GimbalPitchRotateAngle: -45},
+			expectError: `waypoint 1: gimbalRotate sets a pitch angle without a rotate mode, set GimbalRotateMode explicitly ("absoluteAngle" is the safe default)`,
+		},
+		{
+			name:        "Yaw without mode",
+			action:      &GimbalRotateAction{GimbalHeadingYawBase: "north", GimbalYawRotateEnable: true, GimbalYawRotateAngle: 30},
+			expectError: "waypoint 1: gimbalRotate sets a yaw angle",
+		},
+		{
+			name:        "Unknown mode",
+			action:      &GimbalRotateAction{GimbalHeadingYawBase: "north", GimbalRotateMode: "absolute", GimbalPitchRotateEnable: true},
+			expectError: "GimbalRotateMode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Gimbal Mode", 2)
+			waylines.Waypoints[1].Actions = []ActionRequest{{Type: ActionTypeGimbalRotate, Action: tt.action}}
+
+			err := waylines.Validate()
+			if tt.expectError == "" {
+				require.NoError(t, err)
+				_, err = ConvertWaylinesToWPMLMission(waylines)
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectError)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package wpml
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PayloadConfig is a payload mounted next to the primary payload on a
+// multi-gimbal aircraft such as the M300 RTK dual-gimbal configuration.
+type PayloadConfig struct {
+	Model    PayloadModel    `json:"model" validate:"required,payload_model"`
+	Position PayloadPosition `json:"position" validate:"payload_position"`
+}
+
+// payloads returns the primary payload followed by the additional payloads.
+func (w *Waylines) payloads() []PayloadConfig {
+	payloads := make([]PayloadConfig, 0, 1+len(w.AdditionalPayloads))
+	payloads = append(payloads, PayloadConfig{Model: w.PayloadModel, Position: w.PayloadPositionIndex})
+	return append(payloads, w.AdditionalPayloads...)
+}
+
+// validatePayloads checks that every payload occupies its own position, that
+// the aircraft has that position and that the payload can be mounted there.
+// Missions with a single payload are left to the field validation.
+func validatePayloads(w *Waylines) error {
+	if len(w.AdditionalPayloads) == 0 {
+		return nil
+	}
+	dronePositions, knownDrone := PayloadPositionsForDrone(w.DroneModel)
+
+	seen := make(map[PayloadPosition]bool)
+	for _, payload := range w.payloads() {
+		if seen[payload.Position] {
+			return fmt.Errorf(ErrDuplicatePayloadPosition, payload.Position)
+		}
+		seen[payload.Position] = true
+
+		if knownDrone && !slices.Contains(dronePositions, payload.Position) {
+			return fmt.Errorf(ErrPayloadPositionUnsupported, w.DroneModel, payload.Position, dronePositions)
+		}
+		capabilities, ok := CapabilitiesForPayload(payload.Model)
+		if ok && len(capabilities.Positions) > 0 && !slices.Contains(capabilities.Positions, payload.Position) {
+			return fmt.Errorf(ErrPayloadPositionIncompatible, payload.Model, payload.Position, capabilities.Positions)
+		}
+	}
+	return nil
+}
+
+// PrimaryPayloadParam returns the parameter block of the primary payload: the
+// first PayloadParams entry or, for folders built against earlier releases,
+// the deprecated PayloadParam. It is nil when the folder has neither.
+func (f *TemplateFolder) PrimaryPayloadParam() *PayloadParam {
+	if len(f.PayloadParams) == 0 {
+		return f.PayloadParam
+	}
+	return &f.PayloadParams[0]
+}
+
+// syncLegacyPayloadParam keeps the deprecated PayloadParam field in step with
+// PayloadParams after the folder is built or parsed.
+func (f *TemplateFolder) syncLegacyPayloadParam() {
+	f.PayloadParam = nil
+	if len(f.PayloadParams) > 0 {
+		param := f.PayloadParams[0]
+		f.PayloadParam = &param
+	}
+}
+
+// xmlPayloadParams returns the blocks written as wpml:payloadParam, falling
+// back to the deprecated PayloadParam when PayloadParams is empty.
+func (f *TemplateFolder) xmlPayloadParams() []PayloadParam {
+	if len(f.PayloadParams) == 0 && f.PayloadParam != nil {
+		return []PayloadParam{*f.PayloadParam}
+	}
+	return f.PayloadParams
+}
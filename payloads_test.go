@@ -0,0 +1,142 @@
+package wpml
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dualPayloadWaylines() *Waylines {
+	waylines := createValidWaylines("Dual Payload")
+	waylines.DroneModel = DroneM300RTK
+	waylines.PayloadModel = PayloadH20T
+	waylines.AdditionalPayloads = []PayloadConfig{{Model: PayloadL1, Position: PayloadPosition1}}
+	return waylines
+}
+
+func TestConvert_MultiPayloadParams(t *testing.T) {
+	waylines := dualPayloadWaylines()
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	params := mission.Template.Document.Folders[0].PayloadParams
+	require.Len(t, params, 2)
+	assert.Equal(t, 0, params[0].PayloadPositionIndex)
+	assert.Equal(t, 1, params[1].PayloadPositionIndex)
+	assert.Nil(t, params[1].IsRecordPointCloud)
+
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, params, parsed.Template.Document.Folders[0].PayloadParams)
+}
+
+func TestValidatePayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(w *Waylines)
+		wantErr string
+	}{
+		{name: "Dual gimbal", modify: func(w *Waylines) {}},
+		{name: "Single payload", modify: func(w *Waylines) { w.AdditionalPayloads = nil }},
+		{
+			name: "Duplicate position",
+			modify: func(w *Waylines) {
+				w.AdditionalPayloads[0].Position = PayloadPosition0
+			},
+			wantErr: "payload position 0 is used by more than one payload",
+		},
+		{
+			name: "Position missing on the aircraft",
+			modify: func(w *Waylines) {
+				w.DroneModel = DroneM3DSeries
+			},
+			wantErr: "drone 91 has no payload position 1",
+		},
+		{
+			name: "Integrated camera off the main position",
+			modify: func(w *Waylines) {
+				w.AdditionalPayloads[0].Model = PayloadMatrice3TDCamera
+			},
+			wantErr: "payload 81 cannot be mounted at position 1",
+		},
+		{
+			name: "PSDK port",
+			modify: func(w *Waylines) {
+				w.AdditionalPayloads = append(w.AdditionalPayloads, PayloadConfig{Model: PayloadPSDK, Position: PayloadPosition7})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := dualPayloadWaylines()
+			tt.modify(waylines)
+
+			err := waylines.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidatePayloads_InvalidModel(t *testing.T) {
+	waylines := dualPayloadWaylines()
+	waylines.AdditionalPayloads[0].Model = PayloadModel(9999)
+	assert.Error(t, waylines.Validate())
+}
+
+func TestPrimaryPayloadParam(t *testing.T) {
+	waylines := createValidWaylines("Primary")
+	waylines.PayloadModel = PayloadL2
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	folder := mission.Template.Document.Folders[0]
+	require.NotNil(t, folder.PrimaryPayloadParam())
+	assert.Equal(t, int(PayloadPosition0), folder.PrimaryPayloadParam().PayloadPositionIndex)
+
+	assert.Nil(t, (&TemplateFolder{}).PrimaryPayloadParam())
+}
+
+func TestLegacyPayloadParam(t *testing.T) {
+	mission, err := ConvertWaylinesToWPMLMission(dualPayloadWaylines())
+	require.NoError(t, err)
+	folder := &mission.Template.Document.Folders[0]
+	require.NotNil(t, folder.PayloadParam)
+	assert.Equal(t, folder.PayloadParams[0], *folder.PayloadParam)
+
+	data, err := json.Marshal(folder)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"payload_param":`)
+	assert.Contains(t, string(data), `"payload_params":`)
+
+	// JSON stored by earlier releases only has payload_param.
+	var stored map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &stored))
+	delete(stored, "payload_params")
+	data, err = json.Marshal(stored)
+	require.NoError(t, err)
+	var legacy TemplateFolder
+	require.NoError(t, json.Unmarshal(data, &legacy))
+	assert.Empty(t, legacy.PayloadParams)
+	require.NotNil(t, legacy.PrimaryPayloadParam())
+	assert.Equal(t, folder.PayloadParams[0], *legacy.PrimaryPayloadParam())
+
+	mission.Template.Document.Folders[0] = legacy
+	templateData, err := MarshalTemplate(mission.Template)
+	require.NoError(t, err)
+	parsed, err := UnmarshalTemplate(templateData)
+	require.NoError(t, err)
+	parsedFolder := parsed.Document.Folders[0]
+	require.Len(t, parsedFolder.PayloadParams, 1)
+	assert.Equal(t, *legacy.PayloadParam, parsedFolder.PayloadParams[0])
+	assert.Equal(t, parsedFolder.PayloadParams[0], *parsedFolder.PayloadParam)
+}
@@ -37,8 +37,9 @@ type WaypointHeadingParam struct {
 }
 
 type WaypointTurnParam struct {
-	WaypointTurnMode        string   `xml:"wpml:waypointTurnMode" validate:"required" json:"waypoint_turn_mode"`
-	WaypointTurnDampingDist *float64 `xml:"wpml:waypointTurnDampingDist,omitempty" json:"waypoint_turn_damping_dist,omitempty"`
+	WaypointTurnMode          string   `xml:"wpml:waypointTurnMode" validate:"required" json:"waypoint_turn_mode"`
+	WaypointTurnDampingDist   *float64 `xml:"wpml:waypointTurnDampingDist,omitempty" json:"waypoint_turn_damping_dist,omitempty"`
+	WaypointTurnDampingRadius *float64 `xml:"wpml:waypointTurnDampingRadius,omitempty" json:"waypoint_turn_damping_radius,omitempty"`
 }
 
 type WaypointGimbalHeadingParam struct {
@@ -179,6 +180,33 @@ const (
 	HeadingModeFree             = "free"
 )
 
+const (
+	LiDARReturnModeSingleStrongest = "singleReturnStrongest"
+	LiDARReturnModeDual            = "dualReturn"
+	LiDARReturnModeTriple          = "tripleReturn"
+
+	LiDARScanningModeRepetitive    = "repetitive"
+	LiDARScanningModeNonRepetitive = "nonRepetitive"
+
+	// ImageFormatVisible is DJI's spelling of the visible-light image format.
+	ImageFormatVisible = "visable"
+)
+
+const (
+	WaypointWorkTypeContinuous = 0
+	WaypointWorkTypeStopAndGo  = 1
+)
+
+const (
+	GimbalHeadingYawBaseNorth    = "north"
+	GimbalHeadingYawBaseAircraft = "aircraft"
+)
+
+const (
+	GimbalRotateModeAbsoluteAngle = "absoluteAngle"
+	GimbalRotateModeRelativeAngle = "relativeAngle"
+)
+
 const (
 	HeadingPathModeClockwise        = "clockwise"
 	HeadingPathModeCounterClockwise = "counterClockwise"
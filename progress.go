@@ -0,0 +1,30 @@
+package wpml
+
+// ProgressFunc receives progress updates: completed out of total units of
+// work are done. completed increases by one per call and the last call has
+// completed equal to total.
+type ProgressFunc func(completed, total int)
+
+// progressTracker counts steps towards a known total. A nil tracker, or one
+// without a callback, ignores steps, so render code can report progress
+// unconditionally.
+type progressTracker struct {
+	report    ProgressFunc
+	completed int
+	total     int
+}
+
+func newProgressTracker(report ProgressFunc, total int) *progressTracker {
+	if report == nil {
+		return nil
+	}
+	return &progressTracker{report: report, total: total}
+}
+
+func (p *progressTracker) step() {
+	if p == nil {
+		return
+	}
+	p.completed++
+	p.report(p.completed, p.total)
+}
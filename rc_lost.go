@@ -0,0 +1,98 @@
+package wpml
+
+import "fmt"
+
+// FinishRCLostConflict is a combination of finish action and RC-lost action
+// whose behaviors contradict each other, with an explanation of what the
+// aircraft does in each case.
+type FinishRCLostConflict struct {
+	FinishAction FinishAction        `json:"finish_action"`
+	RCLostAction ExecuteRCLostAction `json:"rc_lost_action"`
+	Explanation  string              `json:"explanation"`
+}
+
+// DefaultFinishRCLostConflicts lists the combinations Warnings flags:
+//
+//   - goHome with landing: a completed route returns home, but losing the
+//     remote controller lands the aircraft wherever it is, possibly far from
+//     the pilot.
+//   - autoLand with goBack: a completed route lands at the last waypoint, but
+//     losing the remote controller flies the aircraft home instead, so the
+//     landing site depends on the link.
+//   - noAction with hover: with nothing planned after the route and hovering
+//     on signal loss, the aircraft waits in place until a low battery forces
+//     a return to home.
+//
+// Organizations with their own procedures can pass a different set to
+// ValidateFinishRCLostActions.
+var DefaultFinishRCLostConflicts = []FinishRCLostConflict{
+	{
+		FinishAction: FinishActionGoHome,
+		RCLostAction: ExecuteRCLostActionLanding,
+		Explanation:  "the aircraft returns home after the route but lands in place when the remote controller signal is lost",
+	},
+	{
+		FinishAction: FinishActionAutoLand,
+		RCLostAction: ExecuteRCLostActionGoBack,
+		Explanation:  "the aircraft lands at the last waypoint after the route but flies home when the remote controller signal is lost",
+	},
+	{
+		FinishAction: FinishActionNoAction,
+		RCLostAction: ExecuteRCLostActionHover,
+		Explanation:  "the aircraft hovers in place when the remote controller signal is lost and nothing brings it back until the battery runs low",
+	},
+}
+
+// FinishRCLostConflict returns the entry of conflicts matching the mission's
+// finish action and RC-lost action. The RC-lost action only applies when
+// ExitOnRCLost is executeLostAction; missions that continue the route on
+// signal loss never conflict.
+func (w *Waylines) FinishRCLostConflict(conflicts []FinishRCLostConflict) (FinishRCLostConflict, bool) {
+	if w.ExitOnRCLost != RCLostActionExecuteLostAction {
+		return FinishRCLostConflict{}, false
+	}
+	finishAction := w.finishAction()
+	rcLostAction := w.executeRCLostAction()
+	for _, conflict := range conflicts {
+		if conflict.FinishAction == finishAction && conflict.RCLostAction == rcLostAction {
+			return conflict, true
+		}
+	}
+	return FinishRCLostConflict{}, false
+}
+
+// ValidateFinishRCLostActions returns an error when the mission's finish and
+// RC-lost actions are one of conflicts. Pass DefaultFinishRCLostConflicts for
+// the combinations Warnings reports.
+func (w *Waylines) ValidateFinishRCLostActions(conflicts []FinishRCLostConflict) error {
+	conflict, ok := w.FinishRCLostConflict(conflicts)
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf(ErrFinishRCLostConflict, conflict.FinishAction, conflict.RCLostAction, conflict.Explanation)
+}
+
+func finishRCLostWarnings(w *Waylines) []Warning {
+	conflict, ok := w.FinishRCLostConflict(DefaultFinishRCLostConflicts)
+	if !ok {
+		return nil
+	}
+	return []Warning{{
+		Rule:    WarningRuleFinishRCLostConflict,
+		Message: fmt.Sprintf(ErrFinishRCLostConflict, conflict.FinishAction, conflict.RCLostAction, conflict.Explanation),
+	}}
+}
+
+func (w *Waylines) finishAction() FinishAction {
+	if w.FinishAction == "" {
+		return FinishActionGoHome
+	}
+	return w.FinishAction
+}
+
+func (w *Waylines) executeRCLostAction() ExecuteRCLostAction {
+	if w.ExecuteRCLostAction == "" {
+		return ExecuteRCLostActionHover
+	}
+	return w.ExecuteRCLostAction
+}
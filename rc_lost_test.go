@@ -0,0 +1,81 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinishRCLostConflicts(t *testing.T) {
+	tests := []struct {
+		name         string
+		finish       FinishAction
+		exitOnRCLost RCLostAction
+		rcLost       ExecuteRCLostAction
+		expectWarn   bool
+	}{
+		{name: "Go home and land on signal loss", finish: FinishActionGoHome, exitOnRCLost: RCLostActionExecuteLostAction, rcLost: ExecuteRCLostActionLanding, expectWarn: true},
+		{name: "Auto land and go back on signal loss", finish: FinishActionAutoLand, exitOnRCLost: RCLostActionExecuteLostAction, rcLost: ExecuteRCLostActionGoBack, expectWarn: true},
+		{name: "Default finish action with landing", exitOnRCLost: RCLostActionExecuteLostAction, rcLost: ExecuteRCLostActionLanding, expectWarn: true},
+		{name: "Go home on both", finish: FinishActionGoHome, exitOnRCLost: RCLostActionExecuteLostAction, rcLost: ExecuteRCLostActionGoBack},
+		{name: "Route continues on signal loss", finish: FinishActionGoHome, exitOnRCLost: RCLostActionGoContinue, rcLost: ExecuteRCLostActionLanding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("RC Lost")
+			waylines.FinishAction = tt.finish
+			waylines.ExitOnRCLost = tt.exitOnRCLost
+			waylines.ExecuteRCLostAction = tt.rcLost
+			require.NoError(t, waylines.Validate())
+
+			warnings := finishRCLostWarnings(waylines)
+			err := waylines.ValidateFinishRCLostActions(DefaultFinishRCLostConflicts)
+			if !tt.expectWarn {
+				assert.Empty(t, warnings)
+				assert.NoError(t, err)
+				return
+			}
+			require.Len(t, warnings, 1)
+			assert.Equal(t, WarningRuleFinishRCLostConflict, warnings[0].Rule)
+			require.Error(t, err)
+			assert.Equal(t, warnings[0].Message, err.Error())
+		})
+	}
+}
+
+func TestValidateFinishRCLostActions_CustomConflicts(t *testing.T) {
+	waylines := createValidWaylines("RC Lost")
+	waylines.FinishAction = FinishActionGoHome
+	waylines.ExitOnRCLost = RCLostActionExecuteLostAction
+	waylines.ExecuteRCLostAction = ExecuteRCLostActionLanding
+
+	assert.NoError(t, waylines.ValidateFinishRCLostActions(nil))
+
+	sop := []FinishRCLostConflict{{
+		FinishAction: FinishActionGoHome,
+		RCLostAction: ExecuteRCLostActionHover,
+		Explanation:  "hovering is not allowed by our procedures",
+	}}
+	assert.NoError(t, waylines.ValidateFinishRCLostActions(sop))
+
+	waylines.ExecuteRCLostAction = ExecuteRCLostActionHover
+	err := waylines.ValidateFinishRCLostActions(sop)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hovering is not allowed by our procedures")
+}
+
+func TestConvert_RCLostAction(t *testing.T) {
+	waylines := createValidWaylines("RC Lost")
+	waylines.ExitOnRCLost = RCLostActionExecuteLostAction
+	waylines.ExecuteRCLostAction = ExecuteRCLostActionGoBack
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	config := mission.Template.Document.MissionConfig
+	assert.Equal(t, RCLostActionExecuteLostAction, config.ExitOnRCLost)
+	require.NotNil(t, config.ExecuteRCLostAction)
+	assert.Equal(t, ExecuteRCLostActionGoBack, *config.ExecuteRCLostAction)
+}
@@ -0,0 +1,119 @@
+package wpml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidateWithinRegion returns an error for the first waypoint outside region,
+// for operations geofenced to a country or an approved area. Waypoints inside
+// one of the region's holes are outside it too, while waypoints on a boundary
+// count as inside. Only waypoints are checked; legs between two waypoints
+// inside a concave region can still leave it.
+//
+// The rings use KML coordinates, "longitude,latitude[,altitude]" tuples
+// separated by whitespace, and may repeat the first point at the end. The test
+// treats longitude and latitude as planar coordinates, so regions must not
+// cross the antimeridian.
+func (w *Waylines) ValidateWithinRegion(region Polygon) error {
+	outer, err := parseLinearRing(region.OuterBoundaryIs.LinearRing)
+	if err != nil {
+		return fmt.Errorf(ErrInvalidRegion, "outer boundary", err)
+	}
+	holes := make([][]LatLng, len(region.InnerBoundaryIs))
+	for i, inner := range region.InnerBoundaryIs {
+		if holes[i], err = parseLinearRing(inner.LinearRing); err != nil {
+			return fmt.Errorf(ErrInvalidRegion, fmt.Sprintf("hole %d", i), err)
+		}
+	}
+
+	for i, wp := range w.Waypoints {
+		p := LatLng{Latitude: wp.Latitude, Longitude: wp.Longitude}
+		if ringContains(outer, p) == ringOutside {
+			return fmt.Errorf(ErrWaypointOutsideRegion, i, wp.Latitude, wp.Longitude)
+		}
+		for h, hole := range holes {
+			if ringContains(hole, p) == ringInside {
+				return fmt.Errorf(ErrWaypointInRegionHole, i, wp.Latitude, wp.Longitude, h)
+			}
+		}
+	}
+	return nil
+}
+
+// parseLinearRing reads the coordinates of ring, dropping the closing point.
+func parseLinearRing(ring LinearRing) ([]LatLng, error) {
+	var points []LatLng
+	for _, tuple := range strings.Fields(ring.Coordinates) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf(ErrInvalidRingCoordinate, tuple)
+		}
+		lon, lonErr := strconv.ParseFloat(parts[0], 64)
+		lat, latErr := strconv.ParseFloat(parts[1], 64)
+		if lonErr != nil || latErr != nil {
+			return nil, fmt.Errorf(ErrInvalidRingCoordinate, tuple)
+		}
+		p := LatLng{Latitude: lat, Longitude: lon}
+		if len(points) > 0 && points[len(points)-1] == p {
+			continue
+		}
+		points = append(points, p)
+	}
+	if len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 3 {
+		return nil, fmt.Errorf(ErrRingTooShort, len(points))
+	}
+	return points, nil
+}
+
+type ringPosition int
+
+const (
+	ringOutside ringPosition = iota
+	ringInside
+	ringBoundary
+)
+
+// ringContains locates p against the ring with the even-odd rule, reporting
+// points on an edge separately so callers can decide which side they fall on.
+func ringContains(ring []LatLng, p LatLng) ringPosition {
+	if onRingBoundary(ring, p) {
+		return ringBoundary
+	}
+
+	inside := false
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		// Half-open on latitude so a ray through a vertex counts once.
+		if (a.Latitude > p.Latitude) == (b.Latitude > p.Latitude) {
+			continue
+		}
+		crossing := a.Longitude + (p.Latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude)
+		if p.Longitude < crossing {
+			inside = !inside
+		}
+	}
+	if inside {
+		return ringInside
+	}
+	return ringOutside
+}
+
+func onRingBoundary(ring []LatLng, p LatLng) bool {
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		cross := (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude) - (b.Latitude-a.Latitude)*(p.Longitude-a.Longitude)
+		if cross != 0 {
+			continue
+		}
+		if p.Longitude >= min(a.Longitude, b.Longitude) && p.Longitude <= max(a.Longitude, b.Longitude) &&
+			p.Latitude >= min(a.Latitude, b.Latitude) && p.Latitude <= max(a.Latitude, b.Latitude) {
+			return true
+		}
+	}
+	return false
+}
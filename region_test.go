@@ -0,0 +1,100 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowedRegion is a 0.01° square around 39.90,116.40 with a hole in its
+// north-east quarter.
+func allowedRegion() Polygon {
+	return Polygon{
+		OuterBoundaryIs: OuterBoundaryIs{LinearRing: LinearRing{
+			Coordinates: "116.395,39.895,0 116.405,39.895,0 116.405,39.905,0 116.395,39.905,0 116.395,39.895,0",
+		}},
+		InnerBoundaryIs: []InnerBoundaryIs{{LinearRing: LinearRing{
+			Coordinates: "116.401,39.901 116.404,39.901 116.404,39.904 116.401,39.904",
+		}}},
+	}
+}
+
+func TestValidateWithinRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   [2]float64
+		wantErr string
+	}{
+		{name: "Inside", point: [2]float64{39.898, 116.398}},
+		{name: "On the outer boundary", point: [2]float64{39.895, 116.400}},
+		{name: "On a hole boundary", point: [2]float64{39.901, 116.402}},
+		{name: "Level with a vertex", point: [2]float64{39.901, 116.396}},
+		{name: "Outside", point: [2]float64{39.910, 116.400}, wantErr: "waypoint 1 at 39.910000,116.400000 is outside the allowed region"},
+		{name: "In the hole", point: [2]float64{39.902, 116.402}, wantErr: "waypoint 1 at 39.902000,116.402000 is inside hole 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesAt("Region", [2]float64{39.900, 116.400}, tt.point, [2]float64{39.896, 116.396})
+
+			err := waylines.ValidateWithinRegion(allowedRegion())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateWithinRegion_ConcaveRegion(t *testing.T) {
+	// An L shape missing its north-east quarter.
+	region := Polygon{OuterBoundaryIs: OuterBoundaryIs{LinearRing: LinearRing{
+		Coordinates: "116.39,39.89 116.41,39.89 116.41,39.90 116.40,39.90 116.40,39.91 116.39,39.91",
+	}}}
+
+	inside := waylinesAt("Concave", [2]float64{39.895, 116.405}, [2]float64{39.905, 116.395})
+	assert.NoError(t, inside.ValidateWithinRegion(region))
+
+	outside := waylinesAt("Concave", [2]float64{39.905, 116.405})
+	assert.Error(t, outside.ValidateWithinRegion(region))
+}
+
+func TestValidateWithinRegion_InvalidRegion(t *testing.T) {
+	waylines := createValidWaylines("Region")
+
+	tests := []struct {
+		name    string
+		region  Polygon
+		wantErr string
+	}{
+		{
+			name:    "Bad coordinate",
+			region:  Polygon{OuterBoundaryIs: OuterBoundaryIs{LinearRing: LinearRing{Coordinates: "116.39,39.89 116.41 116.41,39.90"}}},
+			wantErr: `invalid region outer boundary: invalid coordinate "116.41"`,
+		},
+		{
+			name:    "Too few points",
+			region:  Polygon{OuterBoundaryIs: OuterBoundaryIs{LinearRing: LinearRing{Coordinates: "116.39,39.89 116.41,39.89 116.39,39.89"}}},
+			wantErr: "ring has 2 distinct points",
+		},
+		{
+			name: "Bad hole",
+			region: Polygon{
+				OuterBoundaryIs: allowedRegion().OuterBoundaryIs,
+				InnerBoundaryIs: []InnerBoundaryIs{{LinearRing: LinearRing{Coordinates: "north,east"}}},
+			},
+			wantErr: "invalid region hole 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := waylines.ValidateWithinRegion(tt.region)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
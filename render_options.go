@@ -0,0 +1,88 @@
+package wpml
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RenderOptions controls how a Waylines mission is rendered into WPML. The
+// zero value renders with the package defaults.
+type RenderOptions struct {
+	// ActionGroupIDAllocator assigns action group IDs. Defaults to a
+	// MonotonicActionGroupIDAllocator.
+	ActionGroupIDAllocator ActionGroupIDAllocator
+
+	// HeaderComment is written as an XML comment right after the XML
+	// declaration of template.kml and waylines.wpml, e.g. a generator version
+	// and job correlation ID for traceability. DJI Pilot and the aircraft
+	// ignore comments.
+	HeaderComment string
+
+	// TargetVersion selects the WPML schema variant of template.kml and
+	// waylines.wpml. Defaults to WPMLVersionCurrent. Turn damping given in
+	// the form the target does not support is converted using the turn angle
+	// at each waypoint; the render fails where that is not possible.
+	TargetVersion WPMLVersion
+
+	// Progress, if set, is called as the render advances. It runs on the
+	// calling goroutine.
+	Progress ProgressFunc
+
+	// Clock supplies the render time used for the template createTime and
+	// updateTime and for the archive entry modification times. Defaults to
+	// time.Now. Together with IDSource it makes the rendered bytes fully
+	// reproducible, e.g. for golden-file tests.
+	Clock func() time.Time
+
+	// IDSource, if set, supplies action group IDs, one call per group in
+	// waypoint order. It cannot be combined with ActionGroupIDAllocator.
+	// ConvertBatch takes one per mission through BatchOptions.IDSource.
+	IDSource func() int
+}
+
+func (o RenderOptions) Validate() error {
+	if strings.Contains(o.HeaderComment, "--") {
+		return ErrInvalidHeaderComment
+	}
+	if o.ActionGroupIDAllocator != nil && o.IDSource != nil {
+		return ErrConflictingIDSources
+	}
+	switch o.TargetVersion {
+	case "", WPMLVersionCurrent, WPMLVersionLegacy:
+	default:
+		return fmt.Errorf(ErrUnsupportedWPMLVersion, o.TargetVersion)
+	}
+	return nil
+}
+
+func (o RenderOptions) targetVersion() WPMLVersion {
+	if o.TargetVersion == "" {
+		return WPMLVersionCurrent
+	}
+	return o.TargetVersion
+}
+
+func (o RenderOptions) actionGroupIDAllocator() ActionGroupIDAllocator {
+	if o.ActionGroupIDAllocator != nil {
+		return o.ActionGroupIDAllocator
+	}
+	if o.IDSource != nil {
+		return idSourceAllocator(o.IDSource)
+	}
+	return NewMonotonicActionGroupIDAllocator()
+}
+
+func (o RenderOptions) now() time.Time {
+	if o.Clock != nil {
+		return o.Clock()
+	}
+	return time.Now()
+}
+
+// idSourceAllocator adapts RenderOptions.IDSource to ActionGroupIDAllocator.
+type idSourceAllocator func() int
+
+func (f idSourceAllocator) AllocateActionGroupID(site ActionGroupSite) int {
+	return f()
+}
@@ -0,0 +1,90 @@
+package wpml
+
+// LegIntersection reports two route legs that cross. Leg i runs from waypoint
+// i to waypoint i+1.
+type LegIntersection struct {
+	FirstLeg  int     `json:"first_leg"`
+	SecondLeg int     `json:"second_leg"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// FindSelfIntersections returns every pair of non-adjacent legs that cross in
+// the horizontal plane. Heights are ignored, so legs flown at different
+// altitudes are still reported; callers decide whether that is intentional.
+func (w *Waylines) FindSelfIntersections() []LegIntersection {
+	var intersections []LegIntersection
+	if len(w.Waypoints) < 4 {
+		return intersections
+	}
+
+	projection := newLocalProjection(w.Waypoints[0].Latitude, w.Waypoints[0].Longitude)
+	xs := make([]float64, len(w.Waypoints))
+	ys := make([]float64, len(w.Waypoints))
+	for i, wp := range w.Waypoints {
+		xs[i], ys[i] = projection.project(wp.Latitude, wp.Longitude)
+	}
+
+	for i := 0; i < len(w.Waypoints)-1; i++ {
+		for j := i + 2; j < len(w.Waypoints)-1; j++ {
+			t, u, ok := segmentIntersection(xs[i], ys[i], xs[i+1], ys[i+1], xs[j], ys[j], xs[j+1], ys[j+1])
+			// Legs that only touch at their endpoints, such as the first and
+			// last leg of a closed loop, do not cross.
+			if !ok || (isSegmentEnd(t) && isSegmentEnd(u)) {
+				continue
+			}
+			latitude, longitude := projection.unproject(xs[i]+t*(xs[i+1]-xs[i]), ys[i]+t*(ys[i+1]-ys[i]))
+			intersections = append(intersections, LegIntersection{
+				FirstLeg:  i,
+				SecondLeg: j,
+				Latitude:  latitude,
+				Longitude: longitude,
+			})
+		}
+	}
+
+	return intersections
+}
+
+// UncrossLegs removes leg crossings with 2-opt moves: for each crossing pair it
+// reverses the waypoints between the two legs. The first and last waypoints
+// stay in place and each waypoint keeps its own actions. It returns the number
+// of reversals applied. This is a heuristic for routes built from unordered
+// point lists; it reorders waypoints and should not be used on routes whose
+// order is meaningful.
+func (w *Waylines) UncrossLegs() int {
+	maxReversals := len(w.Waypoints) * len(w.Waypoints)
+	reversals := 0
+
+	for reversals < maxReversals {
+		intersections := w.FindSelfIntersections()
+		if len(intersections) == 0 {
+			break
+		}
+
+		first, second := intersections[0].FirstLeg, intersections[0].SecondLeg
+		for l, r := first+1, second; l < r; l, r = l+1, r-1 {
+			w.Waypoints[l], w.Waypoints[r] = w.Waypoints[r], w.Waypoints[l]
+		}
+		reversals++
+	}
+
+	return reversals
+}
+
+func isSegmentEnd(parameter float64) bool {
+	return parameter == 0 || parameter == 1
+}
+
+// PathEfficiency returns the straight-line distance from the first to the last
+// waypoint divided by the flown path length. A direct route scores 1.0; lower
+// values indicate a wandering route, and a closed loop scores 0. Missions with
+// fewer than two distinct positions return 1.0.
+func (w *Waylines) PathEfficiency() float64 {
+	pathLength := w.PathLength()
+	if pathLength == 0 {
+		return 1.0
+	}
+	direct := w.Waypoints[0].position().distanceTo(w.Waypoints[len(w.Waypoints)-1].position())
+	return direct / pathLength
+}
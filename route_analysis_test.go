@@ -0,0 +1,100 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waylinesAt(name string, coordinates ...[2]float64) *Waylines {
+	waylines := createValidWaylines(name)
+	base := waylines.Waypoints[0]
+	waylines.Waypoints = make([]WaylinesWaypoint, len(coordinates))
+	for i, c := range coordinates {
+		waylines.Waypoints[i] = base
+		waylines.Waypoints[i].Latitude = c[0]
+		waylines.Waypoints[i].Longitude = c[1]
+	}
+	return waylines
+}
+
+func TestFindSelfIntersections(t *testing.T) {
+	t.Run("Bow tie crosses once", func(t *testing.T) {
+		waylines := waylinesAt("Bow Tie",
+			[2]float64{39.900, 116.400},
+			[2]float64{39.901, 116.401},
+			[2]float64{39.900, 116.401},
+			[2]float64{39.901, 116.400},
+		)
+
+		intersections := waylines.FindSelfIntersections()
+		require.Len(t, intersections, 1)
+		assert.Equal(t, 0, intersections[0].FirstLeg)
+		assert.Equal(t, 2, intersections[0].SecondLeg)
+		assert.InDelta(t, 39.9005, intersections[0].Latitude, 1e-6)
+		assert.InDelta(t, 116.4005, intersections[0].Longitude, 1e-6)
+	})
+
+	t.Run("Square does not cross", func(t *testing.T) {
+		waylines := waylinesAt("Square",
+			[2]float64{39.900, 116.400},
+			[2]float64{39.900, 116.401},
+			[2]float64{39.901, 116.401},
+			[2]float64{39.901, 116.400},
+			[2]float64{39.900, 116.400},
+		)
+
+		assert.Empty(t, waylines.FindSelfIntersections())
+	})
+
+	t.Run("Short routes cannot cross", func(t *testing.T) {
+		waylines := waylinesAt("Short",
+			[2]float64{39.900, 116.400},
+			[2]float64{39.901, 116.401},
+			[2]float64{39.900, 116.401},
+		)
+
+		assert.Empty(t, waylines.FindSelfIntersections())
+	})
+}
+
+func TestUncrossLegs(t *testing.T) {
+	waylines := waylinesAt("Zig Zag",
+		[2]float64{39.900, 116.400},
+		[2]float64{39.901, 116.401},
+		[2]float64{39.900, 116.401},
+		[2]float64{39.901, 116.400},
+	)
+	waylines.Waypoints[1].Actions = []ActionRequest{photoAction()}
+
+	assert.Equal(t, 1, waylines.UncrossLegs())
+	assert.Empty(t, waylines.FindSelfIntersections())
+
+	assert.Equal(t, 39.900, waylines.Waypoints[0].Latitude)
+	assert.Equal(t, 116.400, waylines.Waypoints[3].Longitude)
+	assert.Len(t, waylines.Waypoints[2].Actions, 1, "actions move with their waypoint")
+
+	assert.Equal(t, 0, waylines.UncrossLegs())
+}
+
+func TestPathEfficiency(t *testing.T) {
+	tests := []struct {
+		name     string
+		coords   [][2]float64
+		expected float64
+	}{
+		{name: "single waypoint", coords: [][2]float64{{39.9, 116.4}}, expected: 1},
+		{name: "repeated position", coords: [][2]float64{{39.9, 116.4}, {39.9, 116.4}}, expected: 1},
+		{name: "straight line", coords: [][2]float64{{39.900, 116.4}, {39.901, 116.4}, {39.902, 116.4}}, expected: 1},
+		{name: "out and back", coords: [][2]float64{{39.900, 116.4}, {39.902, 116.4}, {39.901, 116.4}}, expected: 1.0 / 3},
+		{name: "closed loop", coords: [][2]float64{{39.900, 116.4}, {39.901, 116.4}, {39.900, 116.4}}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesAt("Efficiency", tt.coords...)
+			assert.InDelta(t, tt.expected, waylines.PathEfficiency(), 1e-6)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package wpml
+
+import "time"
+
+// Sample is the estimated aircraft state at a point in time during the
+// flight. Heading is in degrees clockwise from north and GimbalPitch in
+// degrees, negative when pointing down.
+type Sample struct {
+	Time        time.Duration `json:"time"`
+	Latitude    float64       `json:"latitude"`
+	Longitude   float64       `json:"longitude"`
+	Height      float64       `json:"height"`
+	Heading     float64       `json:"heading"`
+	GimbalPitch float64       `json:"gimbal_pitch"`
+}
+
+// sampleStop is the aircraft state while it dwells at a waypoint, after the
+// waypoint's actions have set the heading and gimbal, together with how the
+// leg to the next waypoint changes them.
+type sampleStop struct {
+	arrive, depart float64
+	position       LatLng
+	height         float64
+	heading        float64
+	pitch          float64
+	legHeading     float64
+	legEndPitch    float64
+}
+
+// Sample returns the estimated aircraft state every interval from the first
+// to the last waypoint, timed with the EstimatedDuration model, ending with a
+// sample at the last waypoint. Positions and heights are interpolated along
+// each leg, and the aircraft stays in place for the length of its hovers, so
+// dwells show up as repeated samples at the same position. The heading
+// follows the route in followWayline mode and otherwise holds the last
+// rotateYaw heading. The gimbal pitch changes at gimbalRotate actions and
+// turns evenly across the leg after a gimbalEvenlyRotate action. It returns
+// nil for a non-positive interval or a mission without waypoints.
+func (w *Waylines) Sample(interval time.Duration) []Sample {
+	if interval <= 0 || len(w.Waypoints) == 0 {
+		return nil
+	}
+
+	stops := w.sampleStops()
+	end := stops[len(stops)-1].depart
+	step := interval.Seconds()
+
+	var samples []Sample
+	i := 0
+	for k := 0; ; k++ {
+		t := float64(k) * step
+		if t > end {
+			break
+		}
+		for i < len(stops)-1 && t >= stops[i+1].arrive {
+			i++
+		}
+		samples = append(samples, sampleAt(stops, i, t))
+	}
+	if last := samples[len(samples)-1]; last.Time < secondsToDuration(end) {
+		samples = append(samples, sampleAt(stops, len(stops)-1, end))
+	}
+	return samples
+}
+
+func (w *Waylines) sampleStops() []sampleStop {
+	followRoute := convertGlobalHeadingParam(w).WaypointHeadingMode == HeadingModeFollowWayline
+	stops := make([]sampleStop, len(w.Waypoints))
+
+	heading, pitch, clock := 0.0, 0.0, 0.0
+	if len(w.Waypoints) > 1 {
+		heading = w.Waypoints[0].position().bearingTo(w.Waypoints[1].position())
+	}
+	for i, wp := range w.Waypoints {
+		legEndPitch := pitch
+		for _, action := range w.effectiveActions(wp) {
+			switch a := action.Action.(type) {
+			case *RotateYawAction:
+				heading = a.AircraftHeading
+			case *GimbalRotateAction:
+				if !a.GimbalPitchRotateEnable {
+					continue
+				}
+				if a.GimbalRotateMode == GimbalRotateModeRelativeAngle {
+					pitch += a.GimbalPitchRotateAngle
+				} else {
+					pitch = a.GimbalPitchRotateAngle
+				}
+				legEndPitch = pitch
+			case *GimbalEvenlyRotateAction:
+				legEndPitch = a.GimbalPitchRotateAngle
+			}
+		}
+
+		stop := sampleStop{
+			arrive:      clock,
+			depart:      clock + w.dwellTime(i),
+			position:    wp.position(),
+			height:      w.waypointHeight(wp),
+			heading:     heading,
+			pitch:       pitch,
+			legHeading:  heading,
+			legEndPitch: legEndPitch,
+		}
+		if i < len(w.Waypoints)-1 && followRoute {
+			stop.legHeading = wp.position().bearingTo(w.Waypoints[i+1].position())
+		}
+		stops[i] = stop
+
+		clock = stop.depart + w.legTime(i)
+		heading, pitch = stop.legHeading, legEndPitch
+	}
+	return stops
+}
+
+// sampleAt returns the state at time t, which lies between the arrival at
+// stop i and the arrival at the next stop.
+func sampleAt(stops []sampleStop, i int, t float64) Sample {
+	s := stops[i]
+	sample := Sample{
+		Time:        secondsToDuration(t),
+		Latitude:    s.position.Latitude,
+		Longitude:   s.position.Longitude,
+		Height:      s.height,
+		Heading:     s.heading,
+		GimbalPitch: s.pitch,
+	}
+	if t <= s.depart || i == len(stops)-1 {
+		return sample
+	}
+
+	next := stops[i+1]
+	fraction := 1.0
+	if legTime := next.arrive - s.depart; legTime > 0 {
+		fraction = (t - s.depart) / legTime
+	}
+	sample.Latitude = lerp(s.position.Latitude, next.position.Latitude, fraction)
+	sample.Longitude = lerp(s.position.Longitude, next.position.Longitude, fraction)
+	sample.Height = lerp(s.height, next.height, fraction)
+	sample.Heading = s.legHeading
+	sample.GimbalPitch = lerp(s.pitch, s.legEndPitch, fraction)
+	return sample
+}
+
+func lerp(from, to, fraction float64) float64 {
+	return from + (to-from)*fraction
+}
@@ -0,0 +1,112 @@
+package wpml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleWaylines returns a mission with one leg flown north at 10 m/s.
+func sampleWaylines() *Waylines {
+	waylines := waylinesAt("Sample", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	for i := range waylines.Waypoints {
+		waylines.Waypoints[i].Speed = 10
+	}
+	return waylines
+}
+
+func TestEstimatedDuration(t *testing.T) {
+	waylines := sampleWaylines()
+	leg := waylines.Waypoints[0].position().distanceTo(waylines.Waypoints[1].position())
+	assert.InDelta(t, leg/10, waylines.EstimatedDuration().Seconds(), 1e-6)
+
+	waylines.Waypoints[0].Actions = []ActionRequest{{Type: ActionTypeHover, Action: &HoverAction{HoverTime: 3}}}
+	assert.InDelta(t, leg/10+3, waylines.EstimatedDuration().Seconds(), 1e-6)
+
+	waylines.Waypoints[1].Height = 80
+	assert.Greater(t, waylines.EstimatedDuration().Seconds(), leg/10+3)
+}
+
+func TestConvert_WaylineDurationMatchesEstimate(t *testing.T) {
+	waylines := sampleWaylines()
+	waylines.Waypoints[0].Actions = []ActionRequest{{Type: ActionTypeHover, Action: &HoverAction{HoverTime: 3}}}
+	waylines.Waypoints[1].Height = 80
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+
+	folder := mission.Waylines.Document.Folders[0]
+	assert.InDelta(t, waylines.EstimatedDuration().Seconds(), *folder.Duration, 1e-9)
+	assert.InDelta(t, waylines.legLength3D(0), *folder.Distance, 1e-9)
+}
+
+func TestSample(t *testing.T) {
+	t.Run("Samples span the flight at fixed intervals", func(t *testing.T) {
+		waylines := sampleWaylines()
+		samples := waylines.Sample(time.Second)
+		require.NotEmpty(t, samples)
+
+		start, last := samples[0], samples[len(samples)-1]
+		assert.Equal(t, time.Duration(0), start.Time)
+		assert.Equal(t, waylines.Waypoints[0].Latitude, start.Latitude)
+		assert.Equal(t, waylines.EstimatedDuration(), last.Time)
+		assert.InDelta(t, waylines.Waypoints[1].Latitude, last.Latitude, 1e-9)
+		for i := 1; i < len(samples)-1; i++ {
+			assert.Equal(t, time.Duration(i)*time.Second, samples[i].Time)
+			assert.Greater(t, samples[i].Latitude, samples[i-1].Latitude)
+			assert.InDelta(t, 0, samples[i].Heading, 1e-6)
+		}
+	})
+
+	t.Run("Hover repeats the waypoint position", func(t *testing.T) {
+		waylines := sampleWaylines()
+		waylines.Waypoints[0].Actions = []ActionRequest{{Type: ActionTypeHover, Action: &HoverAction{HoverTime: 3}}}
+		samples := waylines.Sample(time.Second)
+		require.Greater(t, len(samples), 5)
+
+		for _, sample := range samples[:4] {
+			assert.Equal(t, waylines.Waypoints[0].Latitude, sample.Latitude)
+		}
+		assert.Greater(t, samples[4].Latitude, waylines.Waypoints[0].Latitude)
+	})
+
+	t.Run("Evenly rotating gimbal turns across the leg", func(t *testing.T) {
+		waylines := sampleWaylines()
+		waylines.Waypoints[0].Actions = []ActionRequest{{
+			Type:   ActionTypeGimbalEvenlyRotate,
+			Action: &GimbalEvenlyRotateAction{GimbalPitchRotateAngle: -90},
+		}}
+		duration := waylines.EstimatedDuration()
+		samples := waylines.Sample(duration / 2)
+		require.Len(t, samples, 3)
+
+		assert.Equal(t, 0.0, samples[0].GimbalPitch)
+		assert.InDelta(t, -45, samples[1].GimbalPitch, 1e-6)
+		assert.InDelta(t, -90, samples[2].GimbalPitch, 1e-6)
+	})
+
+	t.Run("Gimbal rotate sets the pitch at the waypoint", func(t *testing.T) {
+		waylines := sampleWaylines()
+		waylines.Waypoints[1].Actions = []ActionRequest{gimbalPitchAction(GimbalRotateModeAbsoluteAngle)}
+		samples := waylines.Sample(time.Second)
+
+		assert.Equal(t, 0.0, samples[len(samples)-2].GimbalPitch)
+		assert.Equal(t, -90.0, samples[len(samples)-1].GimbalPitch)
+	})
+
+	t.Run("Manual heading holds the rotate yaw heading", func(t *testing.T) {
+		waylines := sampleWaylines()
+		waylines.AircraftYawMode = "manual"
+		waylines.Waypoints[0].Actions = []ActionRequest{{Type: ActionTypeRotateYaw, Action: &RotateYawAction{AircraftHeading: 90}}}
+		for _, sample := range waylines.Sample(time.Second) {
+			assert.Equal(t, 90.0, sample.Heading)
+		}
+	})
+
+	t.Run("Invalid interval", func(t *testing.T) {
+		assert.Nil(t, sampleWaylines().Sample(0))
+		assert.Nil(t, (&Waylines{}).Sample(time.Second))
+	})
+}
@@ -187,6 +187,11 @@ func MarshalTemplate(template *TemplateDocument) ([]byte, error) {
 		template.WPMLNS = "http://www.dji.com/wpmz/1.0.6"
 	}
 
+	for i := range template.Document.Folders {
+		folder := &template.Document.Folders[i]
+		folder.PayloadParams = folder.xmlPayloadParams()
+	}
+
 	var buf bytes.Buffer
 	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
 
@@ -225,6 +230,9 @@ func UnmarshalTemplate(data []byte) (*TemplateDocument, error) {
 	if err := nbioxml.Unmarshal(data, &template); err != nil {
 		return nil, fmt.Errorf(ErrUnmarshalTemplateDocument, err)
 	}
+	for i := range template.Document.Folders {
+		template.Document.Folders[i].syncLegacyPayloadParam()
+	}
 	return &template, nil
 }
 
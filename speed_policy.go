@@ -0,0 +1,29 @@
+package wpml
+
+import "fmt"
+
+// ValidateMaxSpeedPolicy returns an error when any speed the mission flies at
+// exceeds maxMS, an operator cap that is usually well below the aircraft limit
+// checked by Validate. It checks the global cruise speed, the speed each
+// waypoint flies at and the transitional speed, defaults included, and names
+// the fastest offender.
+func (w *Waylines) ValidateMaxSpeedPolicy(maxMS float64) error {
+	if maxMS <= 0 {
+		return fmt.Errorf(ErrInvalidSpeedPolicy, maxMS)
+	}
+
+	element, fastest := "global transitional", w.transitionalSpeed()
+	if w.GlobalSpeed > fastest {
+		element, fastest = "global", w.GlobalSpeed
+	}
+	for i, wp := range w.Waypoints {
+		if speed := w.waypointSpeed(wp); speed > fastest {
+			element, fastest = fmt.Sprintf("waypoint %d", i), speed
+		}
+	}
+
+	if fastest > maxMS {
+		return fmt.Errorf(ErrSpeedAbovePolicy, element, fastest, maxMS)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMaxSpeedPolicy(t *testing.T) {
+	waylines := waylinesAt("Speed Policy", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400}, [2]float64{39.902, 116.400})
+	waylines.GlobalSpeed = 8
+	waylines.GlobalTransitionalSpeed = 6
+	waylines.Waypoints[0].Speed = 0
+	waylines.Waypoints[1].Speed = 12
+	waylines.Waypoints[2].Speed = 5
+
+	assert.NoError(t, waylines.ValidateMaxSpeedPolicy(12))
+
+	err := waylines.ValidateMaxSpeedPolicy(10)
+	require.Error(t, err)
+	assert.Equal(t, "waypoint 1 speed 12.0m/s exceeds the 10.0m/s speed policy", err.Error())
+
+	waylines.Waypoints[1].Speed = 0
+	err = waylines.ValidateMaxSpeedPolicy(7)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "global speed 8.0m/s", "waypoints without a speed fly at the global speed")
+
+	waylines.GlobalTransitionalSpeed = 0
+	err = waylines.ValidateMaxSpeedPolicy(4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "global speed 8.0m/s", "reports the fastest offender")
+
+	waylines.GlobalSpeed = 5
+	err = waylines.ValidateMaxSpeedPolicy(5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "global transitional speed 6.0m/s", "the default transitional speed is checked")
+}
+
+func TestValidateMaxSpeedPolicy_InvalidPolicy(t *testing.T) {
+	err := createValidWaylines("Speed Policy").ValidateMaxSpeedPolicy(0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid speed policy")
+}
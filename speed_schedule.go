@@ -0,0 +1,111 @@
+package wpml
+
+import (
+	"math"
+	"time"
+)
+
+// ScheduleAcceleration is the acceleration, in m/s², that SpeedSchedule
+// assumes when the aircraft speeds up or slows down. The same rate is used in
+// both directions and regardless of height change, which is close to how DJI
+// enterprise aircraft ramp in waypoint missions but not exact.
+const ScheduleAcceleration = 2.0
+
+// SpeedSample is the modeled ground speed, in m/s, at a point in the flight.
+type SpeedSample struct {
+	Time  time.Duration `json:"time"`
+	Speed float64       `json:"speed"`
+}
+
+// speedPhase is a stretch of the flight over which the speed changes linearly
+// from one value to another.
+type speedPhase struct {
+	duration float64
+	from, to float64
+}
+
+// SpeedSchedule returns the modeled ground speed every second from the first
+// to the last waypoint, ending with a sample at the last waypoint. Each leg
+// is flown at the speed of the waypoint it starts from. The aircraft ramps
+// between speeds at ScheduleAcceleration, and a leg too short to reach its
+// speed peaks below it. It stops at the first and last waypoints, at
+// stop-type turns and at waypoints where it hovers, which shows as samples of
+// speed 0 for the length of the hover. Pass-type turns are flown through at
+// the lower of the speeds of the legs they join. Because of the ramps the
+// schedule runs longer than EstimatedDuration, which assumes constant speeds.
+func (w *Waylines) SpeedSchedule() []SpeedSample {
+	if len(w.Waypoints) == 0 {
+		return nil
+	}
+
+	var phases []speedPhase
+	for i, wp := range w.Waypoints {
+		if dwell := w.dwellTime(i); dwell > 0 {
+			phases = append(phases, speedPhase{duration: dwell})
+		}
+		if i < len(w.Waypoints)-1 {
+			phases = append(phases, legSpeedPhases(w.throughSpeed(i), w.waypointSpeed(wp), w.throughSpeed(i+1), w.legLength3D(i))...)
+		}
+	}
+
+	end := 0.0
+	for _, phase := range phases {
+		end += phase.duration
+	}
+
+	var samples []SpeedSample
+	phase, phaseStart := 0, 0.0
+	for t := 0.0; t <= end; t++ {
+		for phase < len(phases) && t > phaseStart+phases[phase].duration {
+			phaseStart += phases[phase].duration
+			phase++
+		}
+		speed := 0.0
+		if phase < len(phases) {
+			p := phases[phase]
+			speed = lerp(p.from, p.to, (t-phaseStart)/p.duration)
+		}
+		samples = append(samples, SpeedSample{Time: secondsToDuration(t), Speed: speed})
+	}
+	if last := samples[len(samples)-1]; last.Time < secondsToDuration(end) {
+		samples = append(samples, SpeedSample{Time: secondsToDuration(end)})
+	}
+	return samples
+}
+
+// throughSpeed returns the speed at which the aircraft passes waypoint i.
+func (w *Waylines) throughSpeed(i int) float64 {
+	wp := w.Waypoints[i]
+	if i == 0 || i == len(w.Waypoints)-1 || w.dwellTime(i) > 0 || !isPassTurnMode(w.effectiveTurnMode(wp)) {
+		return 0
+	}
+	return math.Min(w.waypointSpeed(w.Waypoints[i-1]), w.waypointSpeed(wp))
+}
+
+// legSpeedPhases returns the phases of a leg of length meters entered at
+// entry m/s, flown at up to cruise m/s, and left at exit m/s.
+func legSpeedPhases(entry, cruise, exit, length float64) []speedPhase {
+	if length <= 0 || cruise <= 0 {
+		return nil
+	}
+	a := ScheduleAcceleration
+	peak := math.Min(cruise, math.Sqrt((2*a*length+entry*entry+exit*exit)/2))
+	if peak < math.Max(entry, exit) {
+		// The leg is too short to change between the entry and exit speeds
+		// at the modeled rate, so the change is spread over the whole leg.
+		return []speedPhase{{duration: 2 * length / (entry + exit), from: entry, to: exit}}
+	}
+
+	cruiseLength := length - (peak*peak-entry*entry)/(2*a) - (peak*peak-exit*exit)/(2*a)
+	var phases []speedPhase
+	for _, phase := range []speedPhase{
+		{duration: (peak - entry) / a, from: entry, to: peak},
+		{duration: math.Max(cruiseLength, 0) / peak, from: peak, to: peak},
+		{duration: (peak - exit) / a, from: peak, to: exit},
+	} {
+		if phase.duration > 0 {
+			phases = append(phases, phase)
+		}
+	}
+	return phases
+}
@@ -0,0 +1,74 @@
+package wpml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func speeds(samples []SpeedSample) []float64 {
+	result := make([]float64, len(samples))
+	for i, sample := range samples {
+		result[i] = sample.Speed
+	}
+	return result
+}
+
+func TestSpeedSchedule(t *testing.T) {
+	t.Run("Ramps up to cruise and down to a stop", func(t *testing.T) {
+		schedule := sampleWaylines().SpeedSchedule()
+		require.NotEmpty(t, schedule)
+
+		values := speeds(schedule)
+		assert.Equal(t, []float64{0, 2, 4, 6, 8, 10, 10}, values[:7])
+		assert.Equal(t, 0.0, values[len(values)-1])
+		for i, sample := range schedule[:len(schedule)-1] {
+			assert.Equal(t, time.Duration(i)*time.Second, sample.Time)
+			assert.LessOrEqual(t, sample.Speed, 10.0)
+		}
+		assert.Greater(t, schedule[len(schedule)-1].Time, sampleWaylines().EstimatedDuration())
+	})
+
+	t.Run("Hover holds speed zero", func(t *testing.T) {
+		waylines := waylinesAt("Hover", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400}, [2]float64{39.902, 116.400})
+		waylines.Waypoints[1].Actions = []ActionRequest{{Type: ActionTypeHover, Action: &HoverAction{HoverTime: 4}}}
+
+		zeros := 0
+		for _, speed := range speeds(waylines.SpeedSchedule()) {
+			if speed == 0 {
+				zeros++
+			}
+		}
+		// Takeoff, the hover and the final stop.
+		assert.GreaterOrEqual(t, zeros, 6)
+	})
+
+	t.Run("Pass-type turns keep moving", func(t *testing.T) {
+		waylines := waylinesAt("Pass", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400}, [2]float64{39.902, 116.400})
+		waylines.GlobalWaypointTurnMode = TurnModeToPointAndPassWithContinuityCurvature
+		waylines.Waypoints[1].WaypointTurnMode = TurnModeToPointAndPassWithContinuityCurvature
+
+		values := speeds(waylines.SpeedSchedule())
+		for _, speed := range values[1 : len(values)-1] {
+			assert.Greater(t, speed, 0.0)
+		}
+		assert.Contains(t, values, 15.0)
+
+		stopping := waylinesAt("Stop", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400}, [2]float64{39.902, 116.400})
+		assert.Greater(t, len(stopping.SpeedSchedule()), len(values))
+	})
+
+	t.Run("Short legs peak below cruise", func(t *testing.T) {
+		waylines := waylinesAt("Short", [2]float64{39.90000, 116.400}, [2]float64{39.90009, 116.400})
+		values := speeds(waylines.SpeedSchedule())
+		for _, speed := range values {
+			assert.Less(t, speed, 15.0)
+		}
+	})
+
+	t.Run("No waypoints", func(t *testing.T) {
+		assert.Nil(t, (&Waylines{}).SpeedSchedule())
+	})
+}
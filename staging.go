@@ -0,0 +1,118 @@
+package wpml
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// SuggestStagingPoint returns the point that minimizes the largest horizontal
+// distance to any waypoint: the center of the smallest circle enclosing the
+// mission. A takeoff spot there gives the most RC and line-of-sight margin to
+// the farthest waypoint, so if any spot keeps the whole mission within a given
+// range, this one does; check it with ValidateStagingPoint. The suggestion is
+// advisory: it ignores terrain, obstacles and whether the ground there is
+// accessible. Missions with a single position return the first waypoint, and
+// missions without waypoints return the zero LatLng.
+//
+// The circle is found with Welzl's randomized incremental algorithm on a local
+// projection around the first waypoint, in expected linear time. The points are
+// shuffled with a fixed seed so the result is deterministic.
+func (w *Waylines) SuggestStagingPoint() LatLng {
+	if len(w.Waypoints) == 0 {
+		return LatLng{}
+	}
+	first := w.Waypoints[0].position()
+
+	projection := newLocalProjection(first.Latitude, first.Longitude)
+	points := make([]circle, len(w.Waypoints))
+	for i, index := range rand.New(rand.NewSource(1)).Perm(len(w.Waypoints)) {
+		wp := w.Waypoints[index]
+		points[i].x, points[i].y = projection.project(wp.Latitude, wp.Longitude)
+	}
+
+	enclosing := smallestEnclosingCircle(points)
+	if enclosing.r == 0 {
+		return first
+	}
+	latitude, longitude := projection.unproject(enclosing.x, enclosing.y)
+	return LatLng{Latitude: latitude, Longitude: longitude}
+}
+
+// ValidateStagingPoint returns an error naming the first waypoint farther than
+// maxRange meters from point, such as the RC or visual line-of-sight range the
+// crew has to keep from the takeoff spot.
+func (w *Waylines) ValidateStagingPoint(point LatLng, maxRange float64) error {
+	for i, wp := range w.Waypoints {
+		if distance := point.distanceTo(wp.position()); distance > maxRange {
+			return fmt.Errorf(ErrWaypointBeyondStagingRange, i, distance, maxRange)
+		}
+	}
+	return nil
+}
+
+// circle is a center and radius on a local projection. A circle of radius zero
+// also stands for a point.
+type circle struct {
+	x, y, r float64
+}
+
+// circleTolerance absorbs rounding when testing whether a point lies on a
+// circle built through it.
+const circleTolerance = 1e-6
+
+func (c circle) contains(p circle) bool {
+	return math.Hypot(p.x-c.x, p.y-c.y) <= c.r+circleTolerance
+}
+
+func smallestEnclosingCircle(points []circle) circle {
+	c := points[0]
+	for i := 1; i < len(points); i++ {
+		if c.contains(points[i]) {
+			continue
+		}
+		c = points[i]
+		for j := 0; j < i; j++ {
+			if c.contains(points[j]) {
+				continue
+			}
+			c = circleThrough2(points[i], points[j])
+			for k := 0; k < j; k++ {
+				if !c.contains(points[k]) {
+					c = circleThrough3(points[i], points[j], points[k])
+				}
+			}
+		}
+	}
+	return c
+}
+
+// circleThrough2 returns the smallest circle through a and b.
+func circleThrough2(a, b circle) circle {
+	return circle{
+		x: (a.x + b.x) / 2,
+		y: (a.y + b.y) / 2,
+		r: math.Hypot(a.x-b.x, a.y-b.y) / 2,
+	}
+}
+
+// circleThrough3 returns the circumcircle of a, b and c. Collinear points have
+// none, so the circle on the two farthest apart is returned instead.
+func circleThrough3(a, b, c circle) circle {
+	bx, by := b.x-a.x, b.y-a.y
+	cx, cy := c.x-a.x, c.y-a.y
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		widest := circleThrough2(a, b)
+		for _, candidate := range []circle{circleThrough2(a, c), circleThrough2(b, c)} {
+			if candidate.r > widest.r {
+				widest = candidate
+			}
+		}
+		return widest
+	}
+
+	ux := (cy*(bx*bx+by*by) - by*(cx*cx+cy*cy)) / d
+	uy := (bx*(cx*cx+cy*cy) - cx*(bx*bx+by*by)) / d
+	return circle{x: a.x + ux, y: a.y + uy, r: math.Hypot(ux, uy)}
+}
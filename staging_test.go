@@ -0,0 +1,63 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestStagingPoint(t *testing.T) {
+	// The two farthest waypoints set the circle; the interior waypoints and
+	// the one near the edge do not move it.
+	waylines := waylinesAt("Staging",
+		[2]float64{39.900, 116.400},
+		[2]float64{39.901, 116.401},
+		[2]float64{39.902, 116.402},
+		[2]float64{39.9043, 116.4008},
+		[2]float64{39.910, 116.400},
+	)
+	point := waylines.SuggestStagingPoint()
+	assert.InDelta(t, 39.905, point.Latitude, 1e-6)
+	assert.InDelta(t, 116.400, point.Longitude, 1e-6)
+
+	// An equilateral-ish triangle is enclosed by its circumcircle, whose
+	// center is equally far from all three corners.
+	triangle := waylinesAt("Triangle",
+		[2]float64{39.900, 116.400},
+		[2]float64{39.900, 116.410},
+		[2]float64{39.908, 116.405},
+	)
+	center := triangle.SuggestStagingPoint()
+	distances := make([]float64, len(triangle.Waypoints))
+	for i, wp := range triangle.Waypoints {
+		distances[i] = center.distanceTo(wp.position())
+	}
+	assert.InDelta(t, distances[0], distances[1], 0.1)
+	assert.InDelta(t, distances[0], distances[2], 0.1)
+}
+
+func TestSuggestStagingPoint_TrivialMissions(t *testing.T) {
+	single := createValidWaylines("Single")
+	assert.Equal(t, single.Waypoints[0].position(), single.SuggestStagingPoint())
+
+	stacked := waylinesAt("Stacked", [2]float64{39.900, 116.400}, [2]float64{39.900, 116.400})
+	assert.Equal(t, stacked.Waypoints[0].position(), stacked.SuggestStagingPoint())
+
+	assert.Equal(t, LatLng{}, (&Waylines{}).SuggestStagingPoint())
+}
+
+func TestValidateStagingPoint(t *testing.T) {
+	waylines := waylinesAt("Range", [2]float64{39.900, 116.400}, [2]float64{39.910, 116.400})
+	point := waylines.SuggestStagingPoint()
+
+	assert.NoError(t, waylines.ValidateStagingPoint(point, 600))
+
+	err := waylines.ValidateStagingPoint(point, 500)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint 0 is 556m from the staging point, beyond the 500m range")
+
+	err = waylines.ValidateStagingPoint(waylines.Waypoints[0].position(), 600)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint 1 is 1112m")
+}
@@ -121,6 +121,7 @@ type PayloadParam struct {
 	SamplingRate         *int    `xml:"wpml:samplingRate,omitempty" json:"sampling_rate,omitempty"`
 	ScanningMode         *string `xml:"wpml:scanningMode,omitempty" json:"scanning_mode,omitempty"`
 	ModelColoringEnable  *int    `xml:"wpml:modelColoringEnable,omitempty" json:"model_coloring_enable,omitempty"`
+	IsRecordPointCloud   *int    `xml:"wpml:isRecordPointCloud,omitempty" json:"is_record_point_cloud,omitempty"`
 	ImageFormat          string  `xml:"wpml:imageFormat" validate:"required" json:"image_format"`
 }
 
@@ -149,13 +150,19 @@ type MappingHeadingParam struct {
 }
 
 type Polygon struct {
-	OuterBoundaryIs OuterBoundaryIs `xml:"outerBoundaryIs" json:"outer_boundary_is"`
+	OuterBoundaryIs OuterBoundaryIs   `xml:"outerBoundaryIs" json:"outer_boundary_is"`
+	InnerBoundaryIs []InnerBoundaryIs `xml:"innerBoundaryIs,omitempty" json:"inner_boundary_is,omitempty"`
 }
 
 type OuterBoundaryIs struct {
 	LinearRing LinearRing `xml:"LinearRing" json:"linear_ring"`
 }
 
+// InnerBoundaryIs is a hole in a Polygon.
+type InnerBoundaryIs struct {
+	LinearRing LinearRing `xml:"LinearRing" json:"linear_ring"`
+}
+
 type LinearRing struct {
 	Coordinates string `xml:"coordinates" json:"coordinates"`
 }
@@ -0,0 +1,121 @@
+package wpml
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// MissionSummary collects the figures clients show before a mission is flown
+// and use for storage and processing estimates.
+type MissionSummary struct {
+	WaypointCount int     `json:"waypoint_count"`
+	ActionCount   int     `json:"action_count"`
+	Distance      float64 `json:"distance"`
+	PhotoCount    int     `json:"photo_count"`
+}
+
+func (w *Waylines) Summary() MissionSummary {
+	summary := MissionSummary{
+		WaypointCount: len(w.Waypoints),
+		Distance:      w.PathLength(),
+		PhotoCount:    w.PhotoCount(),
+	}
+	for _, wp := range w.Waypoints {
+		summary.ActionCount += len(wp.Actions)
+	}
+	return summary
+}
+
+// ActionTypesUsed returns the sorted, distinct action types the rendered
+// mission contains, including actions the converter adds, such as the
+// stabilization hover of stop-and-go missions. It returns an empty slice for
+// missions without actions.
+func (w *Waylines) ActionTypesUsed() []string {
+	types := []string{}
+	for i := range w.Waypoints {
+		for _, group := range w.actionGroups(i) {
+			for _, action := range group.actions {
+				types = append(types, action.Type)
+			}
+		}
+	}
+	slices.Sort(types)
+	return slices.Compact(types)
+}
+
+// PathLength returns the horizontal length in meters of the route from the
+// first to the last waypoint.
+func (w *Waylines) PathLength() float64 {
+	length := 0.0
+	for i := 1; i < len(w.Waypoints); i++ {
+		length += w.Waypoints[i-1].position().distanceTo(w.Waypoints[i].position())
+	}
+	return length
+}
+
+// PhotoCount returns the number of images the mission captures. Each capture
+// action yields one image per lens it records with: the lenses named by the
+// action itself, or otherwise the mission PhotoSettings. Capture actions on a
+// waypoint with a multipleDistance or multipleTiming trigger repeat along the
+// leg to the next waypoint, every TriggerParam meters or seconds at the
+// waypoint speed, including the shot at the waypoint itself. A waypoint
+// ActionTrigger takes the place of its TriggerType and TriggerParam, and an
+// IntervalCapture repeats its photo the same way.
+func (w *Waylines) PhotoCount() int {
+	count := 0
+	for i := range w.Waypoints {
+		for _, group := range w.actionGroups(i) {
+			repeats := w.captureRepeats(i, group.trigger)
+			for _, action := range group.actions {
+				if isCaptureAction(action.Type) {
+					count += repeats * w.lensCount(action)
+				}
+			}
+		}
+	}
+	return count
+}
+
+func (w *Waylines) captureRepeats(i int, trigger ActionTrigger) int {
+	wp := w.Waypoints[i]
+	param := trigger.param()
+	if param <= 0 || i == len(w.Waypoints)-1 {
+		return 1
+	}
+
+	var spacing float64
+	switch trigger.ActionTriggerType {
+	case TriggerTypeMultipleDistance:
+		spacing = param
+	case TriggerTypeMultipleTiming:
+		spacing = param * w.waypointSpeed(wp)
+	default:
+		return 1
+	}
+	if spacing <= 0 {
+		return 1
+	}
+
+	leg := wp.position().distanceTo(w.Waypoints[i+1].position())
+	return int(math.Floor(leg/spacing)) + 1
+}
+
+func (w *Waylines) lensCount(action ActionRequest) int {
+	var lensIndex *string
+	useGlobal := true
+	switch a := action.Action.(type) {
+	case *TakePhotoAction:
+		lensIndex, useGlobal = a.PayloadLensIndex, a.UseGlobalPayloadLensIndex
+	case *PanoShotAction:
+		lensIndex, useGlobal = a.PayloadLensIndex, a.UseGlobalPayloadLensIndex
+	}
+
+	if !useGlobal && lensIndex != nil && *lensIndex != "" {
+		return len(strings.Split(*lensIndex, ","))
+	}
+	if len(w.PhotoSettings) > 0 {
+		return len(w.PhotoSettings)
+	}
+	return 1
+}
@@ -0,0 +1,117 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhotoCount(t *testing.T) {
+	lenses := "wide,ir"
+
+	tests := []struct {
+		name     string
+		setup    func(w *Waylines)
+		expected int
+	}{
+		{
+			name:     "no captures",
+			setup:    func(w *Waylines) {},
+			expected: 0,
+		},
+		{
+			name: "one photo per waypoint",
+			setup: func(w *Waylines) {
+				for i := range w.Waypoints {
+					w.Waypoints[i].Actions = []ActionRequest{photoAction()}
+				}
+			},
+			expected: 3,
+		},
+		{
+			name: "global photo settings count each lens",
+			setup: func(w *Waylines) {
+				w.PhotoSettings = []string{"wide", "zoom", "ir"}
+				w.Waypoints[0].Actions = []ActionRequest{photoAction()}
+			},
+			expected: 3,
+		},
+		{
+			name: "action lenses override photo settings",
+			setup: func(w *Waylines) {
+				w.PhotoSettings = []string{"wide", "zoom", "ir"}
+				w.Waypoints[0].Actions = []ActionRequest{{
+					Type:   ActionTypeTakePhoto,
+					Action: &TakePhotoAction{PayloadLensIndex: &lenses},
+				}}
+			},
+			expected: 2,
+		},
+		{
+			// The first leg is about 111 m long.
+			name: "distance interval",
+			setup: func(w *Waylines) {
+				w.Waypoints[0].TriggerType = TriggerTypeMultipleDistance
+				w.Waypoints[0].TriggerParam = 10
+				w.Waypoints[0].Actions = []ActionRequest{photoAction()}
+			},
+			expected: 12,
+		},
+		{
+			name: "timing interval",
+			setup: func(w *Waylines) {
+				w.Waypoints[0].TriggerType = TriggerTypeMultipleTiming
+				w.Waypoints[0].TriggerParam = 2
+				w.Waypoints[0].Speed = 10
+				w.Waypoints[0].Actions = []ActionRequest{photoAction()}
+			},
+			expected: 6,
+		},
+		{
+			name: "interval on the last waypoint captures once",
+			setup: func(w *Waylines) {
+				w.Waypoints[2].TriggerType = TriggerTypeMultipleDistance
+				w.Waypoints[2].TriggerParam = 10
+				w.Waypoints[2].Actions = []ActionRequest{photoAction()}
+			},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Photos", 3)
+			tt.setup(waylines)
+
+			assert.Equal(t, tt.expected, waylines.PhotoCount())
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	waylines := waylinesWithActionsAt("Summary", 3, 0, 2)
+
+	summary := waylines.Summary()
+	assert.Equal(t, 3, summary.WaypointCount)
+	assert.Equal(t, 2, summary.ActionCount)
+	assert.Equal(t, 2, summary.PhotoCount)
+	assert.InDelta(t, 222.4, summary.Distance, 0.5)
+}
+
+func TestActionTypesUsed(t *testing.T) {
+	waylines := waylinesWithActionsAt("Action Types", 3, 0, 2)
+	assert.Equal(t, []string{ActionTypeTakePhoto}, waylines.ActionTypesUsed())
+
+	waylines.Waypoints[1].Actions = []ActionRequest{
+		{Type: ActionTypeRotateYaw, Action: &RotateYawAction{AircraftHeading: 90}},
+		{Type: ActionTypeGimbalRotate, Action: &GimbalRotateAction{}},
+	}
+	assert.Equal(t, []string{ActionTypeGimbalRotate, ActionTypeRotateYaw, ActionTypeTakePhoto}, waylines.ActionTypesUsed())
+
+	waylines.WorkType = WorkTypeStopAndGo
+	assert.Contains(t, waylines.ActionTypesUsed(), ActionTypeHover, "includes actions added during conversion")
+
+	empty := waylinesWithActionsAt("No Actions", 2)
+	assert.NotNil(t, empty.ActionTypesUsed())
+	assert.Empty(t, empty.ActionTypesUsed())
+}
@@ -10,6 +10,8 @@ type WPMLMission struct {
 	Template  *TemplateDocument
 	Waylines  *WaylinesDocument
 	Resources map[string][]byte
+
+	HeaderComment string
 }
 
 type TemplateDocument struct {
@@ -25,6 +27,8 @@ type TemplateDocumentContent struct {
 	CreateTime int64  `xml:"wpml:createTime,omitempty" json:"create_time,omitempty"`
 	UpdateTime int64  `xml:"wpml:updateTime,omitempty" json:"update_time,omitempty"`
 
+	Metadata *MissionMetadata `xml:"wpml:metadata,omitempty" json:"metadata,omitempty"`
+
 	MissionConfig MissionConfig `xml:"wpml:missionConfig" validate:"required" json:"mission_config"`
 
 	Folders []TemplateFolder `xml:"Folder" validate:"required,dive" json:"folders"`
@@ -65,7 +69,14 @@ type TemplateFolder struct {
 	AutoFlightSpeed float64      `xml:"wpml:autoFlightSpeed" validate:"required,min=1,max=15" json:"auto_flight_speed"`
 
 	WaylineCoordinateSysParam *WaylineCoordinateSysParam `xml:"wpml:waylineCoordinateSysParam,omitempty" json:"wayline_coordinate_sys_param,omitempty"`
-	PayloadParam              *PayloadParam              `xml:"wpml:payloadParam,omitempty" json:"payload_param,omitempty"`
+	// PayloadParams holds one wpml:payloadParam per payload position.
+	PayloadParams []PayloadParam `xml:"wpml:payloadParam,omitempty" json:"payload_params,omitempty"`
+	// PayloadParam is the primary payload's block, as in earlier releases.
+	// Conversion and parsing set it to a copy of the first PayloadParams entry,
+	// and MarshalTemplate writes it when PayloadParams is empty.
+	//
+	// Deprecated: use PayloadParams or PrimaryPayloadParam.
+	PayloadParam *PayloadParam `xml:"-" json:"payload_param,omitempty"`
 
 	GlobalWaypointTurnMode     *string                     `xml:"wpml:globalWaypointTurnMode,omitempty" json:"global_waypoint_turn_mode,omitempty"`
 	GlobalUseStraightLine      *int                        `xml:"wpml:globalUseStraightLine,omitempty" json:"global_use_straight_line,omitempty"`
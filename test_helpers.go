@@ -10,6 +10,7 @@ func createValidWaylines(name string) *Waylines {
 		TemplateType:              TemplateTypeWaypoint,
 		GlobalHeight:              50.0,
 		GlobalSpeed:               15.0,
+		PhotoSettings:             []string{"wide"},
 		ClimbMode:                 "vertical",
 		SafeHeight:                120.0,
 		GlobalRTHHeight:           100.0,
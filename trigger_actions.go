@@ -0,0 +1,90 @@
+package wpml
+
+import "fmt"
+
+// TriggerActionMismatch is a waypoint whose trigger type and actions disagree,
+// so the actions either do not exist or never fire.
+type TriggerActionMismatch struct {
+	WaypointIndex int    `json:"waypoint_index"`
+	TriggerType   string `json:"trigger_type"`
+	Reason        string `json:"reason"`
+}
+
+// TriggerActionMismatches returns the waypoints whose trigger type does not
+// match their actions. A waypoint ActionTrigger takes the place of its
+// TriggerType and TriggerParam:
+//
+//   - a trigger type other than reachPoint, or any ActionTrigger, on a
+//     waypoint without actions. reachPoint is the default trigger, so setting
+//     it alone is not flagged.
+//   - actions under a manual trigger, which does not fire during the route.
+//   - actions under a multipleTiming or multipleDistance trigger without a
+//     positive TriggerParam, which gives the trigger no interval.
+//   - actions under a leg trigger, or an IntervalCapture, on the last
+//     waypoint, which has no following leg to run them on.
+func (w *Waylines) TriggerActionMismatches() []TriggerActionMismatch {
+	var mismatches []TriggerActionMismatch
+	for i, wp := range w.Waypoints {
+		if reason := w.triggerActionMismatch(i, wp); reason != "" {
+			mismatches = append(mismatches, TriggerActionMismatch{
+				WaypointIndex: i,
+				TriggerType:   wp.actionTrigger().ActionTriggerType,
+				Reason:        reason,
+			})
+		}
+		if wp.IntervalCapture != nil && i == len(w.Waypoints)-1 {
+			mismatches = append(mismatches, TriggerActionMismatch{
+				WaypointIndex: i,
+				TriggerType:   wp.IntervalCapture.TriggerType,
+				Reason:        "the last waypoint has no following leg to run the interval capture on",
+			})
+		}
+	}
+	return mismatches
+}
+
+func (w *Waylines) triggerActionMismatch(i int, wp WaylinesWaypoint) string {
+	trigger := wp.actionTrigger()
+	if len(wp.Actions) == 0 {
+		if wp.ActionTrigger != nil || (wp.TriggerType != "" && wp.TriggerType != TriggerTypeReachPoint) {
+			return "the trigger is set but the waypoint has no actions"
+		}
+		return ""
+	}
+
+	switch trigger.ActionTriggerType {
+	case TriggerTypeManual:
+		return "the manual trigger does not fire actions during the route"
+	case TriggerTypeMultipleTiming, TriggerTypeMultipleDistance:
+		if trigger.param() <= 0 {
+			return "the interval trigger needs a positive trigger_param"
+		}
+	}
+	if isLegTrigger(trigger.ActionTriggerType) && i == len(w.Waypoints)-1 {
+		return "the last waypoint has no following leg to run the actions on"
+	}
+	return ""
+}
+
+// ValidateTriggerActions returns an error for the first waypoint whose trigger
+// type does not match its actions. Warnings reports every such waypoint.
+func (w *Waylines) ValidateTriggerActions() error {
+	mismatches := w.TriggerActionMismatches()
+	if len(mismatches) == 0 {
+		return nil
+	}
+	m := mismatches[0]
+	return fmt.Errorf(ErrTriggerActionMismatch, m.WaypointIndex, m.TriggerType, m.Reason)
+}
+
+func triggerActionWarnings(w *Waylines) []Warning {
+	var warnings []Warning
+	for _, m := range w.TriggerActionMismatches() {
+		warnings = append(warnings, Warning{
+			Rule:            WarningRuleTriggerActionMismatch,
+			WaypointIndices: []int{m.WaypointIndex},
+			Message:         fmt.Sprintf(ErrTriggerActionMismatch, m.WaypointIndex, m.TriggerType, m.Reason),
+		})
+	}
+	return warnings
+}
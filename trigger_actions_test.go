@@ -0,0 +1,99 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerActionMismatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(w *Waylines)
+		index  int
+		reason string
+	}{
+		{
+			name:  "reach point without actions",
+			setup: func(w *Waylines) { w.Waypoints[1].TriggerType = TriggerTypeReachPoint },
+			index: -1,
+		},
+		{
+			name:  "actions without a trigger type",
+			setup: func(w *Waylines) { w.Waypoints[1].Actions = []ActionRequest{photoAction()} },
+			index: -1,
+		},
+		{
+			name: "interval trigger with cleared actions",
+			setup: func(w *Waylines) {
+				w.Waypoints[1].TriggerType = TriggerTypeMultipleDistance
+				w.Waypoints[1].TriggerParam = 20
+			},
+			index:  1,
+			reason: "has no actions",
+		},
+		{
+			name: "manual trigger",
+			setup: func(w *Waylines) {
+				w.Waypoints[0].TriggerType = TriggerTypeManual
+				w.Waypoints[0].Actions = []ActionRequest{photoAction()}
+			},
+			index:  0,
+			reason: "does not fire actions",
+		},
+		{
+			name: "interval trigger without an interval",
+			setup: func(w *Waylines) {
+				w.Waypoints[1].TriggerType = TriggerTypeMultipleTiming
+				w.Waypoints[1].Actions = []ActionRequest{photoAction()}
+			},
+			index:  1,
+			reason: "positive trigger_param",
+		},
+		{
+			name: "leg trigger on the last waypoint",
+			setup: func(w *Waylines) {
+				w.Waypoints[2].TriggerType = TriggerTypeBetweenAdjacentPoints
+				w.Waypoints[2].Actions = []ActionRequest{photoAction()}
+			},
+			index:  2,
+			reason: "no following leg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := waylinesWithActionsAt("Triggers", 3)
+			tt.setup(waylines)
+
+			mismatches := waylines.TriggerActionMismatches()
+			if tt.index < 0 {
+				assert.Empty(t, mismatches)
+				assert.NoError(t, waylines.ValidateTriggerActions())
+				return
+			}
+			require.Len(t, mismatches, 1)
+			assert.Equal(t, tt.index, mismatches[0].WaypointIndex)
+			assert.Contains(t, mismatches[0].Reason, tt.reason)
+
+			err := waylines.ValidateTriggerActions()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.reason)
+		})
+	}
+}
+
+func TestTriggerActionWarnings(t *testing.T) {
+	waylines := waylinesWithActionsAt("Triggers", 3)
+	waylines.Waypoints[0].TriggerType = TriggerTypeMultipleDistance
+	waylines.Waypoints[0].TriggerParam = 20
+	waylines.Waypoints[2].TriggerType = TriggerTypeManual
+
+	warnings := triggerActionWarnings(waylines)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, WarningRuleTriggerActionMismatch, warnings[0].Rule)
+	assert.Equal(t, []int{0}, warnings[0].WaypointIndices)
+	assert.Equal(t, []int{2}, warnings[1].WaypointIndices)
+	assert.Contains(t, warnings[1].Message, "waypoint 2 trigger type manual")
+}
@@ -0,0 +1,184 @@
+package wpml
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// turnDampingSeconds is how long before reaching a pass-type waypoint the
+	// aircraft starts to turn: the recommended damping distance is the
+	// distance flown in that time at the waypoint speed.
+	turnDampingSeconds = 1.0
+	// minTurnDampingDist matches the damping distance DJI uses by default.
+	minTurnDampingDist = 0.2
+	// maxRadiusConversionTurn is the sharpest turn, in radians, at which a
+	// damping radius is converted to a distance. The distance r·tan(θ/2)
+	// grows without bound as the turn nears a reversal.
+	maxRadiusConversionTurn = 170 * math.Pi / 180
+)
+
+// RecommendedTurnDamping returns a turn damping distance in meters for a
+// waypoint flown at speed m/s whose shorter adjacent leg is legLength meters.
+// Faster waypoints turn earlier to avoid a sharp corner; the result is capped
+// at half the leg so the turns at both ends of a leg never overlap.
+func RecommendedTurnDamping(speed float64, legLength float64) float64 {
+	damping := math.Max(speed*turnDampingSeconds, minTurnDampingDist)
+	return math.Min(damping, legLength/2)
+}
+
+// ApplyRecommendedDamping fills TurnDampingDist on every interior waypoint that
+// uses a pass-type turn mode and has no damping distance of its own, using
+// RecommendedTurnDamping with the waypoint's effective speed and its shorter
+// adjacent leg. Stop-type turns and the first and last waypoints are left
+// unchanged because the aircraft does not turn through them.
+func (w *Waylines) ApplyRecommendedDamping() {
+	for i := 1; i < len(w.Waypoints)-1; i++ {
+		wp := &w.Waypoints[i]
+		if wp.TurnDampingDist > 0 || !isPassTurnMode(w.effectiveTurnMode(*wp)) {
+			continue
+		}
+		wp.TurnDampingDist = RecommendedTurnDamping(w.waypointSpeed(*wp), w.shortestAdjacentLeg(i))
+	}
+}
+
+func validateTurnDamping(w *Waylines) error {
+	for i := 1; i < len(w.Waypoints)-1; i++ {
+		wp := w.Waypoints[i]
+		if !isPassTurnMode(w.effectiveTurnMode(wp)) {
+			continue
+		}
+		damping, err := w.turnDampingDist(i)
+		if err != nil {
+			return err
+		}
+		if leg := w.shortestAdjacentLeg(i); damping > leg/2 {
+			return fmt.Errorf(ErrTurnDampingTooLarge, i, damping, leg)
+		}
+	}
+	return nil
+}
+
+func validateTurnDampingForm(w *Waylines) error {
+	for i, wp := range w.Waypoints {
+		if wp.TurnDampingDist > 0 && wp.TurnDampingRadius > 0 {
+			return fmt.Errorf(ErrTurnDampingDistAndRadius, i)
+		}
+	}
+	return nil
+}
+
+// turnDampingDist returns the damping distance of waypoint i: its own
+// distance, its radius converted to a distance, or the global distance. A
+// radius is not converted at turns sharper than maxRadiusConversionTurn.
+func (w *Waylines) turnDampingDist(i int) (float64, error) {
+	wp := w.Waypoints[i]
+	switch {
+	case wp.TurnDampingDist > 0:
+		return wp.TurnDampingDist, nil
+	case wp.TurnDampingRadius > 0:
+		angle := w.turnAngle(i)
+		if angle > maxRadiusConversionTurn {
+			return 0, fmt.Errorf(ErrTurnDampingRadiusNearReversal, i, wp.TurnDampingRadius, angle*180/math.Pi, maxRadiusConversionTurn*180/math.Pi)
+		}
+		return wp.TurnDampingRadius * math.Tan(angle/2), nil
+	default:
+		return w.GlobalTurnDampingDist, nil
+	}
+}
+
+// turnDampingRadius returns the damping radius of waypoint i, converting a
+// damping distance when needed. Where the aircraft does not turn, at the
+// first and last waypoint or on a straight line, the global distance has no
+// effect and becomes a zero radius, while a distance set on the waypoint
+// itself cannot be converted and is an error.
+func (w *Waylines) turnDampingRadius(i int) (float64, error) {
+	wp := w.Waypoints[i]
+	if wp.TurnDampingRadius > 0 {
+		return wp.TurnDampingRadius, nil
+	}
+	dist, err := w.turnDampingDist(i)
+	if err != nil || dist == 0 {
+		return 0, err
+	}
+	half := math.Tan(w.turnAngle(i) / 2)
+	if half < 1e-9 {
+		if wp.TurnDampingDist > 0 {
+			return 0, fmt.Errorf(ErrTurnDampingDistWithoutTurn, i, wp.TurnDampingDist)
+		}
+		return 0, nil
+	}
+	return dist / half, nil
+}
+
+// turnAngle returns the heading change in radians, from 0 to π, the aircraft
+// makes at waypoint i. The first and last waypoint have no turn. For a turn of
+// angle θ flown as a circular arc of radius r, the turn starts r·tan(θ/2)
+// before the waypoint.
+func (w *Waylines) turnAngle(i int) float64 {
+	if i == 0 || i >= len(w.Waypoints)-1 {
+		return 0
+	}
+	projection := newLocalProjection(w.Waypoints[i].Latitude, w.Waypoints[i].Longitude)
+	inX, inY := projection.project(w.Waypoints[i-1].Latitude, w.Waypoints[i-1].Longitude)
+	outX, outY := projection.project(w.Waypoints[i+1].Latitude, w.Waypoints[i+1].Longitude)
+	if (inX == 0 && inY == 0) || (outX == 0 && outY == 0) {
+		return 0
+	}
+	// The incoming direction points from the previous waypoint to this one.
+	angle := math.Abs(math.Atan2(outY, outX) - math.Atan2(-inY, -inX))
+	if angle > math.Pi {
+		angle = 2*math.Pi - angle
+	}
+	return angle
+}
+
+// effectiveTurnMode resolves the turn mode the converter emits for a waypoint.
+func (w *Waylines) effectiveTurnMode(waypoint WaylinesWaypoint) string {
+	switch {
+	case waypoint.WaypointTurnMode != "":
+		return waypoint.WaypointTurnMode
+	case w.GlobalWaypointTurnMode != "":
+		return w.GlobalWaypointTurnMode
+	case w.WorkType == WorkTypeStopAndGo:
+		return TurnModeToPointAndStopWithDiscontinuityCurvature
+	default:
+		return TurnModeToPointAndStopWithContinuityCurvature
+	}
+}
+
+// turnModeAt resolves the turn mode the converter emits for waypoint i. The
+// last waypoint follows WaypointEndType when its own turn mode does not
+// already brake or pass as requested.
+func (w *Waylines) turnModeAt(i int) string {
+	turnMode := w.effectiveTurnMode(w.Waypoints[i])
+	if i != len(w.Waypoints)-1 {
+		return turnMode
+	}
+	switch {
+	case w.WaypointEndType == WaypointEndTypeStop && isPassTurnMode(turnMode):
+		return TurnModeToPointAndStopWithDiscontinuityCurvature
+	case w.WaypointEndType == WaypointEndTypePass && !isPassTurnMode(turnMode):
+		return TurnModeToPointAndPassWithContinuityCurvature
+	default:
+		return turnMode
+	}
+}
+
+func (w *Waylines) waypointSpeed(waypoint WaylinesWaypoint) float64 {
+	if waypoint.Speed > 0 {
+		return waypoint.Speed
+	}
+	return w.GlobalSpeed
+}
+
+// shortestAdjacentLeg returns the length in meters of the shorter of the legs
+// into and out of interior waypoint i.
+func (w *Waylines) shortestAdjacentLeg(i int) float64 {
+	point := w.Waypoints[i].position()
+	return math.Min(point.distanceTo(w.Waypoints[i-1].position()), point.distanceTo(w.Waypoints[i+1].position()))
+}
+
+func (wp WaylinesWaypoint) position() LatLng {
+	return LatLng{Latitude: wp.Latitude, Longitude: wp.Longitude}
+}
@@ -0,0 +1,140 @@
+package wpml
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendedTurnDamping(t *testing.T) {
+	tests := []struct {
+		name      string
+		speed     float64
+		legLength float64
+		expected  float64
+	}{
+		{name: "scales with speed", speed: 8, legLength: 100, expected: 8},
+		{name: "minimum at low speed", speed: 0.1, legLength: 100, expected: minTurnDampingDist},
+		{name: "clamped to half the leg", speed: 12, legLength: 10, expected: 5},
+		{name: "zero-length leg", speed: 5, legLength: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, RecommendedTurnDamping(tt.speed, tt.legLength), 1e-9)
+		})
+	}
+}
+
+func TestApplyRecommendedDamping(t *testing.T) {
+	// Legs of roughly 111 m and 11 m.
+	waylines := waylinesAt("Damping", [2]float64{39.9000, 116.4}, [2]float64{39.9010, 116.4}, [2]float64{39.9011, 116.4}, [2]float64{39.9020, 116.4})
+	waylines.GlobalWaypointTurnMode = TurnModeCoordinateTurn
+	waylines.Waypoints[2].WaypointTurnMode = TurnModeToPointAndStopWithContinuityCurvature
+	for i := range waylines.Waypoints {
+		waylines.Waypoints[i].Speed = 12
+	}
+
+	waylines.ApplyRecommendedDamping()
+
+	assert.Zero(t, waylines.Waypoints[0].TurnDampingDist, "first waypoint has no turn")
+	assert.InDelta(t, waylines.shortestAdjacentLeg(1)/2, waylines.Waypoints[1].TurnDampingDist, 1e-9)
+	assert.Zero(t, waylines.Waypoints[2].TurnDampingDist, "stop-type turns are not damped")
+	assert.Zero(t, waylines.Waypoints[3].TurnDampingDist, "last waypoint has no turn")
+	require.NoError(t, waylines.Validate())
+
+	waylines.Waypoints[1].TurnDampingDist = 20
+	err := waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waypoint 1: turn damping distance")
+}
+
+func TestTurnAngle(t *testing.T) {
+	// A right-angle corner followed by a straight continuation.
+	waylines := waylinesAt("Angles", [2]float64{39.9000, 116.4000}, [2]float64{39.9010, 116.4000}, [2]float64{39.9010, 116.4013}, [2]float64{39.9010, 116.4026})
+
+	assert.Zero(t, waylines.turnAngle(0))
+	assert.InDelta(t, math.Pi/2, waylines.turnAngle(1), 1e-3)
+	assert.InDelta(t, 0, waylines.turnAngle(2), 1e-9)
+	assert.Zero(t, waylines.turnAngle(3))
+}
+
+func TestConvert_TurnDampingTargetVersion(t *testing.T) {
+	newWaylines := func() *Waylines {
+		waylines := waylinesAt("Damping Versions", [2]float64{39.9000, 116.4000}, [2]float64{39.9010, 116.4000}, [2]float64{39.9010, 116.4013}, [2]float64{39.9010, 116.4026})
+		waylines.GlobalWaypointTurnMode = TurnModeCoordinateTurn
+		return waylines
+	}
+
+	t.Run("radius converted to distance for current target", func(t *testing.T) {
+		waylines := newWaylines()
+		waylines.Waypoints[1].TurnDampingRadius = 10
+
+		mission, err := ConvertWaylinesToWPMLMission(waylines)
+		require.NoError(t, err)
+
+		param := mission.Waylines.Document.Folders[0].Placemarks[1].WaypointTurnParam
+		assert.InDelta(t, 10, *param.WaypointTurnDampingDist, 0.05, "a right angle starts the turn one radius early")
+		assert.Nil(t, param.WaypointTurnDampingRadius)
+	})
+
+	t.Run("distance converted to radius for legacy target", func(t *testing.T) {
+		waylines := newWaylines()
+		waylines.Waypoints[1].TurnDampingDist = 10
+		waylines.GlobalTurnDampingDist = 1
+
+		mission, err := ConvertWaylinesToWPMLMissionWithOptions(waylines, RenderOptions{TargetVersion: WPMLVersionLegacy})
+		require.NoError(t, err)
+
+		placemarks := mission.Waylines.Document.Folders[0].Placemarks
+		assert.Nil(t, placemarks[1].WaypointTurnParam.WaypointTurnDampingDist)
+		require.NotNil(t, placemarks[1].WaypointTurnParam.WaypointTurnDampingRadius)
+		assert.InDelta(t, 10, *placemarks[1].WaypointTurnParam.WaypointTurnDampingRadius, 0.05)
+		require.NotNil(t, placemarks[2].WaypointTurnParam.WaypointTurnDampingRadius)
+		assert.Zero(t, *placemarks[2].WaypointTurnParam.WaypointTurnDampingRadius, "the global distance has no effect on a straight line")
+
+		templateParam := mission.Template.Document.Folders[0].Placemarks[1].WaypointTurnParam
+		assert.Nil(t, templateParam.WaypointTurnDampingDist)
+		assert.NotNil(t, templateParam.WaypointTurnDampingRadius, "template.kml uses the legacy form too")
+	})
+
+	t.Run("waypoint distance on a straight line for legacy target", func(t *testing.T) {
+		waylines := newWaylines()
+		waylines.Waypoints[2].TurnDampingDist = 1
+
+		_, err := ConvertWaylinesToWPMLMissionWithOptions(waylines, RenderOptions{TargetVersion: WPMLVersionLegacy})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoint 2: turn damping distance 1.0m cannot be converted")
+	})
+
+	t.Run("radius at a near reversal", func(t *testing.T) {
+		waylines := waylinesAt("Reversal", [2]float64{39.9000, 116.4000}, [2]float64{39.9010, 116.4000}, [2]float64{39.9000, 116.40005})
+		waylines.GlobalWaypointTurnMode = TurnModeCoordinateTurn
+		waylines.Waypoints[1].TurnDampingRadius = 5
+
+		_, err := ConvertWaylinesToWPMLMission(waylines)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoint 1: turn damping radius 5.0m cannot be converted")
+
+		_, err = ConvertWaylinesToWPMLMissionWithOptions(waylines, RenderOptions{TargetVersion: WPMLVersionLegacy})
+		require.Error(t, err, "Validate rejects the turn for every target")
+	})
+
+	t.Run("both forms set", func(t *testing.T) {
+		waylines := newWaylines()
+		waylines.Waypoints[1].TurnDampingDist = 5
+		waylines.Waypoints[1].TurnDampingRadius = 5
+
+		_, err := ConvertWaylinesToWPMLMission(waylines)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoint 1 sets both")
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		_, err := ConvertWaylinesToWPMLMissionWithOptions(newWaylines(), RenderOptions{TargetVersion: "2.0"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported WPML target version")
+	})
+}
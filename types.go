@@ -66,6 +66,9 @@ const (
 	PayloadMatrice4DCamera  PayloadModel = 98
 	PayloadMatrice4TDCamera PayloadModel = 99
 
+	PayloadL1 PayloadModel = 90742
+	PayloadL2 PayloadModel = 84
+
 	PayloadFPVCamera PayloadModel = 39
 
 	PayloadDockCamera PayloadModel = 165
@@ -118,6 +121,18 @@ const (
 	HeightModeRealTimeFollowSurface HeightMode = "realTimeFollowSurface"
 )
 
+// WPMLVersion selects the schema variant a mission is rendered for.
+type WPMLVersion string
+
+const (
+	// WPMLVersionCurrent describes turn damping as the distance before the
+	// waypoint at which the turn starts (waypointTurnDampingDist).
+	WPMLVersionCurrent WPMLVersion = "current"
+	// WPMLVersionLegacy targets older firmware whose schema describes turn
+	// damping as a turn radius (waypointTurnDampingRadius).
+	WPMLVersionLegacy WPMLVersion = "legacy"
+)
+
 type ExecuteHeightMode string
 
 const (
@@ -126,6 +141,28 @@ const (
 	ExecuteHeightModeRealTimeFollowSurface ExecuteHeightMode = "realTimeFollowSurface"
 )
 
+type WorkType string
+
+const (
+	WorkTypeContinuous WorkType = "continuous"
+	WorkTypeStopAndGo  WorkType = "stopAndGo"
+)
+
+// WaypointEndType sets how the aircraft arrives at the last waypoint. WPML has
+// no element of its own for it: the turn mode of the last waypoint decides
+// whether the aircraft brakes on the point, so that is what the converter
+// emits.
+type WaypointEndType string
+
+const (
+	// WaypointEndTypeStop brakes on the last waypoint, so final shots are
+	// taken on the point, before the finish action starts.
+	WaypointEndTypeStop WaypointEndType = "stop"
+	// WaypointEndTypePass flies through the last waypoint into the finish
+	// action without braking; the aircraft overshoots slightly.
+	WaypointEndTypePass WaypointEndType = "pass"
+)
+
 type CoordinateMode string
 
 const (
@@ -28,6 +28,21 @@ func (w *WPMLValidator) registerCustomValidators() {
 	w.validator.RegisterValidation("action_type", w.validateActionType)
 	w.validator.RegisterValidation("required_for_drone", w.validateRequiredForDrone)
 	w.validator.RegisterValidation("required_for_payload", w.validateRequiredForPayload)
+	w.validator.RegisterStructValidation(w.validateWaypointHeights, Waylines{})
+}
+
+// heightRangeTag names the struct-level check of waypoint heights against the
+// range of their height mode, which a field tag cannot express because the
+// mode is usually set on the mission.
+const heightRangeTag = "height_range"
+
+func (w *WPMLValidator) validateWaypointHeights(sl validator.StructLevel) {
+	waylines := sl.Current().Interface().(Waylines)
+	for i, wp := range waylines.Waypoints {
+		if waylines.validateWaypointHeight(i) != nil {
+			sl.ReportError(wp.Height, fmt.Sprintf("Waypoints[%d].Height", i), "Height", heightRangeTag, string(waylines.waypointHeightMode(i)))
+		}
+	}
 }
 
 func (w *WPMLValidator) validatePayloadPosition(fl validator.FieldLevel) bool {
@@ -81,6 +96,8 @@ func (w *WPMLValidator) validatePayloadModel(fl validator.FieldLevel) bool {
 		int(PayloadMatrice4TCamera),
 		int(PayloadMatrice4DCamera),
 		int(PayloadMatrice4TDCamera),
+		int(PayloadL1),
+		int(PayloadL2),
 		int(PayloadFPVCamera),
 		int(PayloadDockCamera),
 		int(PayloadAuxiliaryCamera),
@@ -144,6 +161,10 @@ func (w *WPMLValidator) ValidateStruct(s interface{}) error {
 	return w.validator.Struct(s)
 }
 
+func (w *WPMLValidator) ValidateStructExcept(s interface{}, fields ...string) error {
+	return w.validator.StructExcept(s, fields...)
+}
+
 func (w *WPMLValidator) ValidateVar(field interface{}, tag string) error {
 	return w.validator.Var(field, tag)
 }
@@ -378,6 +399,8 @@ func (w *WPMLValidator) formatValidationError(e validator.FieldError) string {
 		return fmt.Sprintf("field '%s' Drone modelinvalid", e.Field())
 	case "payload_model":
 		return fmt.Sprintf("field '%s' 负载modelinvalid", e.Field())
+	case heightRangeTag:
+		return fmt.Sprintf("field '%s' is outside the range allowed in %s height mode", e.Field(), e.Param())
 	default:
 		return fmt.Sprintf("field '%s' validationfailure: %s", e.Field(), e.Tag())
 	}
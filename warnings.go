@@ -0,0 +1,98 @@
+package wpml
+
+import "fmt"
+
+// Warning is an advisory finding about a mission. Unlike a validation error it
+// does not prevent conversion; it points at settings that are legal but likely
+// to surprise an operator.
+type Warning struct {
+	Rule            string `json:"rule"`
+	WaypointIndices []int  `json:"waypoint_indices,omitempty"`
+	Message         string `json:"message"`
+}
+
+const (
+	WarningRuleMixedHeightModes         = "mixedHeightModes"
+	WarningRuleUnstoppedIntervalCapture = "unstoppedIntervalCapture"
+	WarningRuleUnsafeFileName           = "unsafeFileName"
+	WarningRuleTransitionalSpeed        = "transitionalSpeed"
+	WarningRuleStaleGimbalPitch         = "staleGimbalPitch"
+	WarningRuleLowMappingOverlap        = "lowMappingOverlap"
+	WarningRuleFinishRCLostConflict     = "finishRCLostConflict"
+	WarningRuleTriggerActionMismatch    = "triggerActionMismatch"
+	WarningRuleGSProUnmapped            = "gsProUnmapped"
+	WarningRuleFirstTransitClimb        = "firstTransitClimb"
+	WarningRuleMissionComplexity        = "missionComplexity"
+)
+
+var warningRules = []func(w *Waylines) []Warning{
+	heightModeMixingWarnings,
+	intervalCaptureWarnings,
+	fileNameWarnings,
+	transitionalSpeedWarnings,
+	staleGimbalPitchWarnings,
+	mappingOverlapWarnings,
+	finishRCLostWarnings,
+	triggerActionWarnings,
+	firstTransitClimbWarnings,
+	complexityWarnings,
+}
+
+// Warnings runs every advisory rule against the mission and returns the
+// findings in rule order. It does not validate the mission.
+func (w *Waylines) Warnings() []Warning {
+	var warnings []Warning
+	for _, rule := range warningRules {
+		warnings = append(warnings, rule(w)...)
+	}
+	return warnings
+}
+
+// MixedHeightModeWaypoints returns the indices of waypoints whose own
+// HeightMode differs from the mission HeightType.
+func (w *Waylines) MixedHeightModeWaypoints() []int {
+	var indices []int
+	for i := range w.Waypoints {
+		if w.waypointHeightMode(i) != w.heightMode() {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// ValidateHeightModeMixing returns an error when any waypoint uses a height
+// mode other than the mission height mode, for firmware that cannot mix
+// height references within a mission. Validate already rejects mixed heights
+// that do not convert into the mission mode; Warnings reports the rest.
+func (w *Waylines) ValidateHeightModeMixing() error {
+	if indices := w.MixedHeightModeWaypoints(); len(indices) > 0 {
+		return fmt.Errorf(ErrMixedHeightModes, indices, w.heightMode())
+	}
+	return nil
+}
+
+// transitionalSpeedWarnings flags a transitional speed above the cruise speed,
+// which makes for an aggressive launch. Speeds above the aircraft maximum are
+// already rejected by Validate.
+func transitionalSpeedWarnings(w *Waylines) []Warning {
+	transitional := w.transitionalSpeed()
+	if w.GlobalSpeed <= 0 || transitional <= w.GlobalSpeed {
+		return nil
+	}
+	return []Warning{{
+		Rule:    WarningRuleTransitionalSpeed,
+		Message: fmt.Sprintf(ErrTransitionalSpeedAboveCruise, transitional, w.GlobalSpeed),
+	}}
+}
+
+func heightModeMixingWarnings(w *Waylines) []Warning {
+	indices := w.MixedHeightModeWaypoints()
+	if len(indices) == 0 {
+		return nil
+	}
+	return []Warning{{
+		Rule:            WarningRuleMixedHeightModes,
+		WaypointIndices: indices,
+		Message:         fmt.Sprintf(ErrMixedHeightModes, indices, w.heightMode()),
+	}}
+}
@@ -0,0 +1,150 @@
+package wpml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightModeMixing(t *testing.T) {
+	waylines := createValidWaylines("Mixed Modes")
+	waylines.Waypoints = append(waylines.Waypoints, waylines.Waypoints[0], waylines.Waypoints[0])
+
+	t.Run("uniform modes", func(t *testing.T) {
+		waylines.Waypoints[1].HeightMode = HeightModeRelativeToStartPoint
+		defer func() { waylines.Waypoints[1].HeightMode = "" }()
+
+		assert.Empty(t, waylines.MixedHeightModeWaypoints())
+		assert.NoError(t, waylines.ValidateHeightModeMixing())
+		assert.Empty(t, waylines.Warnings())
+	})
+
+	t.Run("mixed modes", func(t *testing.T) {
+		waylines.Waypoints[2].HeightMode = HeightModeEGM96
+		defer func() { waylines.Waypoints[2].HeightMode = "" }()
+
+		assert.Equal(t, []int{2}, waylines.MixedHeightModeWaypoints())
+		err := waylines.Validate()
+		require.Error(t, err, "EGM96 heights need the takeoff point to convert")
+		assert.Contains(t, err.Error(), "waypoint 2: converting a EGM96 height")
+
+		waylines.TakeOffRefPointLatitude = 39.9090
+		waylines.TakeOffRefPointLongitude = 116.3970
+		waylines.TakeOffRefPointHeight = 10
+		defer func() {
+			waylines.TakeOffRefPointLatitude, waylines.TakeOffRefPointLongitude, waylines.TakeOffRefPointHeight = 0, 0, 0
+		}()
+		assert.NoError(t, waylines.Validate())
+
+		err = waylines.ValidateHeightModeMixing()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waypoints [2]")
+
+		warnings := waylines.Warnings()
+		require.Len(t, warnings, 1)
+		assert.Equal(t, WarningRuleMixedHeightModes, warnings[0].Rule)
+		assert.Equal(t, []int{2}, warnings[0].WaypointIndices)
+	})
+}
+
+func TestWaypointHeightMode_HeightRange(t *testing.T) {
+	waylines := createValidWaylines("Per-Point Mode")
+	waylines.Waypoints[0].Height = 800
+	require.Error(t, waylines.Validate())
+
+	waylines.Waypoints[0].HeightMode = HeightModeEGM96
+	err := waylines.Validate()
+	require.Error(t, err, "an EGM96 height cannot be related to the takeoff point without it")
+	assert.Contains(t, err.Error(), "needs a takeoff reference point")
+
+	waylines.TakeOffRefPointLatitude = 39.9090
+	waylines.TakeOffRefPointLongitude = 116.3970
+	waylines.TakeOffRefPointHeight = 100
+	err = waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is 700.0m in the mission height mode relativeToStartPoint, outside the 5 to 500m range")
+
+	waylines.TakeOffRefPointHeight = 450
+	assert.NoError(t, waylines.Validate())
+
+	waylines.Waypoints[0].HeightMode = HeightModeAboveGroundLevel
+	waylines.Waypoints[0].Height = 80
+	err = waylines.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "without terrain elevation")
+
+	waylines.Waypoints[0].HeightMode = "sideways"
+	assert.Error(t, waylines.Validate())
+}
+
+func TestConvert_MixedHeightModes(t *testing.T) {
+	waylines := waylinesAt("Mixed Render", [2]float64{39.900, 116.400}, [2]float64{39.901, 116.400})
+	waylines.TakeOffRefPointLatitude = 39.8995
+	waylines.TakeOffRefPointLongitude = 116.4000
+	waylines.TakeOffRefPointHeight = 450
+	waylines.Waypoints[0].Height = 60
+	waylines.Waypoints[1].HeightMode = HeightModeEGM96
+	waylines.Waypoints[1].Height = 800
+	require.NoError(t, waylines.Validate())
+
+	mission, err := ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	kmz, err := CreateKmzBuffer(mission)
+	require.NoError(t, err)
+	parsed, err := ParseKMZBuffer(kmz.Bytes())
+	require.NoError(t, err)
+
+	folder := parsed.Waylines.Document.Folders[0]
+	assert.Equal(t, ExecuteHeightModeRelativeToStartPoint, folder.ExecuteHeightMode)
+	assert.Equal(t, 60.0, *folder.Placemarks[0].ExecuteHeight)
+	assert.Equal(t, 350.0, *folder.Placemarks[1].ExecuteHeight, "800m EGM96 is 350m above the 450m takeoff point")
+	assert.Equal(t, 350.0, *parsed.Template.Document.Folders[0].Placemarks[1].Height)
+
+	waylines.HeightType = HeightModeEGM96
+	waylines.Waypoints[0].HeightMode = HeightModeRelativeToStartPoint
+	waylines.Waypoints[1].HeightMode = ""
+	mission, err = ConvertWaylinesToWPMLMission(waylines)
+	require.NoError(t, err)
+	template := mission.Template.Document.Folders[0]
+	assert.Equal(t, HeightModeEGM96, template.WaylineCoordinateSysParam.HeightMode)
+	assert.Equal(t, 510.0, *template.Placemarks[0].Height)
+	assert.Equal(t, 800.0, *template.Placemarks[1].Height)
+
+	waylines.Waypoints[0].HeightMode = HeightModeAboveGroundLevel
+	_, err = ConvertWaylinesToWPMLMission(waylines)
+	assert.Error(t, err, "heights that cannot be converted are not emitted")
+}
+
+func TestTransitionalSpeedWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		transitional float64
+		cruise       float64
+		messages     []string
+	}{
+		{name: "below cruise", transitional: 8, cruise: 10},
+		{name: "equal to cruise", transitional: 10, cruise: 10},
+		{name: "above cruise", transitional: 12, cruise: 5, messages: []string{"12.0m/s", "cruise speed 5.0m/s"}},
+		{name: "default above cruise", transitional: 0, cruise: 4, messages: []string{"6.0m/s", "cruise speed 4.0m/s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waylines := createValidWaylines("Transitional")
+			waylines.GlobalTransitionalSpeed = tt.transitional
+			waylines.GlobalSpeed = tt.cruise
+
+			warnings := waylines.Warnings()
+			if len(tt.messages) == 0 {
+				assert.Empty(t, warnings)
+				return
+			}
+			require.Len(t, warnings, 1)
+			assert.Equal(t, WarningRuleTransitionalSpeed, warnings[0].Rule)
+			for _, message := range tt.messages {
+				assert.Contains(t, warnings[0].Message, message)
+			}
+		})
+	}
+}